@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"blackjack/internal/achievement"
+	"blackjack/internal/api"
+	"blackjack/internal/config"
+	"blackjack/internal/database"
+	"blackjack/internal/ledger"
+	"blackjack/internal/player"
+	"blackjack/internal/round"
+	"blackjack/internal/token"
+)
+
+func main() {
+	cfg, err := config.LoadLocal()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.New(cfg.DatabasePath)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	log.Println("Database connected")
+
+	server := api.NewServer(cfg,
+		player.NewRepository(db.DB),
+		round.NewRepository(db.DB),
+		token.NewRepository(db.DB),
+		ledger.NewRepository(db.DB),
+		achievement.NewRepository(db.DB),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	server.Restore()
+	go server.RunSweeper(ctx)
+
+	srv := &http.Server{Addr: cfg.APIListen, Handler: server.Handler()}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("API listening on %s", cfg.APIListen)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	var runErr error
+	select {
+	case runErr = <-errCh:
+	case <-ctx.Done():
+		// дожидаемся запросов в обработке, чтобы не рассчитать игру посреди хода
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shut down API server: %v", err)
+		}
+		cancel()
+	}
+	if errors.Is(runErr, http.ErrServerClosed) {
+		runErr = nil
+	}
+
+	server.Close()
+	if err := db.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+
+	if runErr != nil {
+		log.Fatalf("API server error: %v", runErr)
+	}
+	log.Println("API stopped, games settled and database closed")
+}
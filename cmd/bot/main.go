@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os/signal"
+	"syscall"
 
-	"blackjack/internal/bot"
+	"blackjack/internal/achievement"
 	"blackjack/internal/config"
 	"blackjack/internal/database"
+	"blackjack/internal/ledger"
 	"blackjack/internal/player"
+	"blackjack/internal/round"
+	"blackjack/internal/telegram"
+	"blackjack/internal/token"
+	"blackjack/internal/tournament"
 )
 
 func main() {
@@ -19,18 +27,32 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
 	log.Println("Database connected")
 
 	playerRepo := player.NewRepository(db.DB)
+	roundRepo := round.NewRepository(db.DB)
+	tokenRepo := token.NewRepository(db.DB)
+	ledgerRepo := ledger.NewRepository(db.DB)
+	achievementRepo := achievement.NewRepository(db.DB)
+	tournamentRepo := tournament.NewRepository(db.DB)
 
-	b, err := bot.New(cfg, playerRepo)
+	b, err := telegram.New(cfg, playerRepo, roundRepo, tokenRepo, ledgerRepo, achievementRepo, tournamentRepo)
 	if err != nil {
 		log.Fatalf("Failed to create bot: %v", err)
 	}
 
-	if err := b.Run(); err != nil {
-		log.Fatalf("Bot error: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	runErr := b.Run(ctx)
+
+	if err := db.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+
+	if runErr != nil {
+		log.Fatalf("Bot error: %v", runErr)
 	}
+	log.Println("Bot stopped, games saved and database closed")
 }
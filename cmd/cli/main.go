@@ -0,0 +1,130 @@
+// Команда cli — локальный фронтенд бота в терминале: те же команды и кнопки,
+// что в Telegram, без токена и сети. Кнопки нажимаются вводом их номера.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"blackjack/internal/achievement"
+	"blackjack/internal/bot"
+	"blackjack/internal/config"
+	"blackjack/internal/database"
+	"blackjack/internal/ledger"
+	"blackjack/internal/player"
+	"blackjack/internal/round"
+	"blackjack/internal/token"
+	"blackjack/internal/tournament"
+	"blackjack/internal/transport"
+)
+
+const usage = `Commands are the same as in the bot: /start, /play 100, /help ...
+Type a button number to press it. Extra commands:
+  :user <id> [name]  act as another user (for group tables)
+  :quit              save games and exit`
+
+func main() {
+	dbPath := flag.String("db", "./cli.db", "SQLite database path")
+	userID := flag.Int64("user", 1, "user ID to play as")
+	name := flag.String("name", "player", "display name")
+	lang := flag.String("lang", "ru", "Telegram language code used for the first contact")
+	group := flag.Bool("group", false, "play at a group table instead of a private chat")
+	flag.Parse()
+
+	cfg, err := config.LoadLocal()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.New(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	term := newTerminal(os.Stdout)
+	h := bot.NewHandler(term, cfg, player.NewRepository(db.DB), round.NewRepository(db.DB), token.NewRepository(db.DB), ledger.NewRepository(db.DB), achievement.NewRepository(db.DB), tournament.NewRepository(db.DB), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.Restore()
+	go h.RunSweeper(ctx)
+
+	// в личном чате ID чата совпадает с ID пользователя, группы — отрицательные
+	chatID := *userID
+	if *group {
+		chatID = -1
+	}
+	from := transport.User{ID: *userID, Name: *name, LanguageCode: *lang}
+	names := map[int64]string{from.ID: from.Name}
+
+	fmt.Println(usage)
+
+	actions := 0
+	in := bufio.NewScanner(os.Stdin)
+loop:
+	for fmt.Print("\n> "); in.Scan(); fmt.Print("\n> ") {
+		line := strings.TrimSpace(in.Text())
+
+		switch fields := strings.Fields(line); {
+		case line == "":
+			continue
+
+		case line == ":quit" || line == ":q":
+			break loop
+
+		case fields[0] == ":user":
+			if len(fields) < 2 {
+				fmt.Println(usage)
+				continue
+			}
+			id, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				fmt.Println("invalid user ID")
+				continue
+			}
+			if len(fields) > 2 {
+				names[id] = fields[2]
+			} else if names[id] == "" {
+				names[id] = fmt.Sprintf("player%d", id)
+			}
+			from.ID = id
+			from.Name = names[id]
+			if !*group {
+				chatID = id
+			}
+
+		default:
+			n, err := strconv.Atoi(line)
+			if err != nil {
+				h.HandleMessage(&transport.Message{ChatID: chatID, Group: *group, From: from, Text: line})
+				continue
+			}
+
+			messageID, data, ok := term.press(n)
+			if !ok {
+				fmt.Println("no such button")
+				continue
+			}
+			actions++
+			h.HandleCallback(&transport.Action{
+				ID:        strconv.Itoa(actions),
+				ChatID:    chatID,
+				MessageID: messageID,
+				Group:     *group,
+				From:      from,
+				Data:      data,
+			})
+		}
+	}
+
+	cancel()
+	h.Shutdown()
+	if err := db.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"blackjack/internal/transport"
+)
+
+// terminal — транспорт, который печатает сообщения в консоль и нумерует кнопки
+type terminal struct {
+	mu  sync.Mutex
+	out io.Writer
+
+	nextID    int
+	keyboards map[int]transport.Keyboard
+	// последнее сообщение с кнопками — на него указывают номера, введенные игроком
+	last int
+}
+
+func newTerminal(out io.Writer) *terminal {
+	return &terminal{
+		out:       out,
+		keyboards: make(map[int]transport.Keyboard),
+	}
+}
+
+func (t *terminal) Send(chatID int64, text string, kb transport.Keyboard) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nextID++
+	fmt.Fprintf(t.out, "\n── #%d ──\n", t.nextID)
+	t.print(t.nextID, text, kb)
+	return t.nextID, nil
+}
+
+func (t *terminal) Edit(chatID int64, messageID int, text string, kb transport.Keyboard) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fmt.Fprintf(t.out, "\n── #%d (edited) ──\n", messageID)
+	t.print(messageID, text, kb)
+	return nil
+}
+
+// SendPhoto сохраняет картинку во временный файл и печатает путь к нему над подписью
+func (t *terminal) SendPhoto(chatID int64, photo []byte, caption string, kb transport.Keyboard) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nextID++
+	fmt.Fprintf(t.out, "\n── #%d ──\n", t.nextID)
+	if err := t.printPhoto(t.nextID, photo); err != nil {
+		return 0, err
+	}
+	t.print(t.nextID, caption, kb)
+	return t.nextID, nil
+}
+
+func (t *terminal) EditPhoto(chatID int64, messageID int, photo []byte, caption string, kb transport.Keyboard) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fmt.Fprintf(t.out, "\n── #%d (edited) ──\n", messageID)
+	if err := t.printPhoto(messageID, photo); err != nil {
+		return err
+	}
+	t.print(messageID, caption, kb)
+	return nil
+}
+
+func (t *terminal) printPhoto(id int, photo []byte) error {
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("blackjack-%d.png", id))
+	if err := os.WriteFile(path, photo, 0o644); err != nil {
+		return fmt.Errorf("failed to save photo: %w", err)
+	}
+	fmt.Fprintf(t.out, "🖼 %s\n", path)
+	return nil
+}
+
+func (t *terminal) Answer(actionID, text string) error {
+	if text == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fmt.Fprintf(t.out, "» %s\n", text)
+	return nil
+}
+
+func (t *terminal) print(id int, text string, kb transport.Keyboard) {
+	fmt.Fprintln(t.out, text)
+
+	if kb == nil {
+		delete(t.keyboards, id)
+		if t.last == id {
+			t.last = 0
+		}
+		return
+	}
+
+	t.keyboards[id] = kb
+	t.last = id
+
+	var sb strings.Builder
+	n := 0
+	for _, row := range kb {
+		for _, b := range row {
+			n++
+			fmt.Fprintf(&sb, "[%d] %s  ", n, b.Text)
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprint(t.out, sb.String())
+}
+
+// press находит n-ю кнопку последнего сообщения с кнопками
+func (t *terminal) press(n int) (messageID int, data string, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, row := range t.keyboards[t.last] {
+		for _, b := range row {
+			n--
+			if n == 0 {
+				return t.last, b.Data, true
+			}
+		}
+	}
+	return 0, "", false
+}
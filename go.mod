@@ -7,4 +7,4 @@ require (
 	github.com/joho/godotenv v1.5.1
 )
 
-require github.com/mattn/go-sqlite3 v1.14.32 // indirect
+require github.com/mattn/go-sqlite3 v1.14.32
@@ -0,0 +1,81 @@
+package achievement
+
+import (
+	"blackjack/internal/game"
+)
+
+type ID string
+
+const (
+	FirstWin        ID = "first_win"
+	FirstBlackjack  ID = "first_blackjack"
+	SuitedBlackjack ID = "suited_blackjack"
+	WinStreak5      ID = "win_streak_5"
+	DoubleEleven    ID = "double_eleven"
+	SplitFour       ID = "split_four"
+)
+
+// All — все достижения в порядке показа
+var All = []ID{FirstWin, FirstBlackjack, SuitedBlackjack, WinStreak5, DoubleEleven, SplitFour}
+
+// Round — итог раунда для одного игрока: его руки в игре и серия побед после расчета
+type Round struct {
+	Game      *game.State
+	Hands     []int
+	WinStreak int
+}
+
+// Earned возвращает достижения, заработанные в раунде. Уже открытые
+// тоже попадают сюда — повторы отсеивает Repository.Unlock.
+func Earned(r Round) []ID {
+	g := r.Game
+	var ids []ID
+
+	won := false
+	boxes := make(map[int][]*game.Hand)
+	for _, i := range r.Hands {
+		hand := g.Hands[i]
+		boxes[hand.Box] = append(boxes[hand.Box], hand)
+
+		result, _ := g.HandResult(hand)
+		if result == game.ResultPlayerWin || result == game.ResultBlackjack || result == game.ResultBonus {
+			won = true
+		}
+
+		if result == game.ResultBlackjack {
+			ids = append(ids, FirstBlackjack)
+			if game.Suit(hand.Cards[0]) != "" && game.Suit(hand.Cards[0]) == game.Suit(hand.Cards[1]) {
+				ids = append(ids, SuitedBlackjack)
+			}
+		}
+
+		if hand.IsDouble && result == game.ResultPlayerWin && game.CalculateScore(hand.Cards[:2]) == 11 {
+			ids = append(ids, DoubleEleven)
+		}
+	}
+
+	if won {
+		ids = append(ids, FirstWin)
+	}
+	if r.WinStreak >= 5 {
+		ids = append(ids, WinStreak5)
+	}
+
+	// бокс, разделенный на четыре руки, принес больше, чем на нем стояло
+	for _, hands := range boxes {
+		if len(hands) < game.MaxSplitHands {
+			continue
+		}
+		bet, payout := 0, 0
+		for _, hand := range hands {
+			_, win := g.HandResult(hand)
+			bet += hand.Real()
+			payout += win
+		}
+		if payout > bet {
+			ids = append(ids, SplitFour)
+		}
+	}
+
+	return ids
+}
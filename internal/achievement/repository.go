@@ -0,0 +1,77 @@
+package achievement
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type Unlock struct {
+	ID         ID
+	UnlockedAt time.Time
+}
+
+type Repository interface {
+	Unlock(userID int64, ids []ID) ([]ID, error)
+	List(userID int64) ([]Unlock, error)
+}
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Unlock открывает достижения и возвращает только новые
+func (r *SQLiteRepository) Unlock(userID int64, ids []ID) ([]ID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var unlocked []ID
+	for _, id := range ids {
+		res, err := tx.Exec(`
+			INSERT OR IGNORE INTO achievements (user_id, achievement) VALUES (?, ?)
+		`, userID, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unlock achievement: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			unlocked = append(unlocked, id)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return unlocked, nil
+}
+
+func (r *SQLiteRepository) List(userID int64) ([]Unlock, error) {
+	rows, err := r.db.Query(`
+		SELECT achievement, unlocked_at FROM achievements
+		WHERE user_id = ? ORDER BY unlocked_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievements: %w", err)
+	}
+	defer rows.Close()
+
+	var unlocks []Unlock
+	for rows.Next() {
+		var u Unlock
+		if err := rows.Scan(&u.ID, &u.UnlockedAt); err != nil {
+			return nil, err
+		}
+		unlocks = append(unlocks, u)
+	}
+	return unlocks, rows.Err()
+}
@@ -0,0 +1,379 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blackjack/internal/achievement"
+	"blackjack/internal/fair"
+	"blackjack/internal/game"
+	"blackjack/internal/ledger"
+	"blackjack/internal/player"
+	"blackjack/internal/round"
+)
+
+const (
+	ActionHit    = "hit"
+	ActionStand  = "stand"
+	ActionDouble = "double"
+	ActionSplit  = "split"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+type startRequest struct {
+	Bets []int `json:"bets"`
+}
+
+type actionRequest struct {
+	Action string `json:"action"`
+}
+
+// ============== РАУНДЫ ==============
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request, userID int64) {
+	var req startRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	bets := req.Bets
+	if len(bets) == 0 {
+		bets = []int{s.cfg.DefaultBet}
+	}
+	if len(bets) > s.cfg.MaxBoxes {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d boxes per round", s.cfg.MaxBoxes))
+		return
+	}
+
+	total := 0
+	for _, bet := range bets {
+		if bet < s.cfg.MinBet || bet > s.cfg.MaxBet {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("bet must be between %d and %d", s.cfg.MinBet, s.cfg.MaxBet))
+			return
+		}
+		total += bet
+	}
+
+	s.startMu.Lock()
+	defer s.startMu.Unlock()
+
+	if s.games.Get(userID) != nil {
+		writeError(w, http.StatusConflict, "round already in progress")
+		return
+	}
+
+	p, err := s.players.GetOrCreate(userID, s.cfg.StartBalance, s.cfg.DefaultBet)
+	if err != nil {
+		log.Printf("Failed to load player %d: %v", userID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	if !p.CanAfford(total) {
+		writeError(w, http.StatusPaymentRequired, fmt.Sprintf("insufficient funds, balance %d", p.Balance))
+		return
+	}
+
+	// списываем до раунда: баланс в базе мог измениться после загрузки игрока
+	if err := s.changeBalance(p, ledger.KindBet, -total); err != nil {
+		if errors.Is(err, ledger.ErrNegative) {
+			writeError(w, http.StatusPaymentRequired, fmt.Sprintf("insufficient funds, balance %d", p.Balance))
+			return
+		}
+		log.Printf("Failed to charge bet: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	// бот играет в личном чате под тем же ключом, раунд берется атомарно
+	rnd, err := round.Begin(s.rounds, userID, true, p.Seed(), total)
+	if err != nil {
+		log.Printf("Failed to start round: %v", err)
+		if err := s.changeBalance(p, ledger.KindRefund, total); err != nil {
+			log.Printf("Failed to refund bet: %v", err)
+		}
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	p.LastBet = bets[len(bets)-1]
+
+	g := game.NewState(bets, fair.NewSource(rnd.ServerSeed, rnd.ClientSeed, rnd.ID))
+	g.RoundID = rnd.ID
+	g.PlayerID = userID
+	g.BlackjackPays = s.cfg.BlackjackPays
+	g.LastAction = time.Now()
+	s.games.Set(userID, g)
+
+	g.Lock()
+	defer g.Unlock()
+
+	// Блэкджек у дилера или у всех боксов — раунд решен сразу
+	var unlocked []achievement.ID
+	if game.IsBlackjack(g.DealerCards) || g.AllHandsComplete() {
+		unlocked = s.settle(userID, g, p)
+	} else {
+		s.savePlayer(p)
+		s.saveRound(g)
+	}
+
+	v := s.view(g, p)
+	v.Achievements = unlocked
+	writeJSON(w, http.StatusCreated, v)
+}
+
+func (s *Server) handleCurrent(w http.ResponseWriter, r *http.Request, userID int64) {
+	g := s.games.Get(userID)
+	if g == nil {
+		writeError(w, http.StatusNotFound, "no active round")
+		return
+	}
+
+	p, err := s.players.GetOrCreate(userID, s.cfg.StartBalance, s.cfg.DefaultBet)
+	if err != nil {
+		log.Printf("Failed to load player %d: %v", userID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	writeJSON(w, http.StatusOK, s.view(g, p))
+}
+
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request, userID int64) {
+	var req actionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	g := s.games.Get(userID)
+	if g == nil {
+		writeError(w, http.StatusConflict, "no active round")
+		return
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	// раунд мог завершиться, пока ждали блокировку
+	hand := g.Current()
+	if !g.IsActive || hand == nil {
+		writeError(w, http.StatusConflict, "no active round")
+		return
+	}
+
+	p, err := s.players.GetOrCreate(userID, s.cfg.StartBalance, s.cfg.DefaultBet)
+	if err != nil {
+		log.Printf("Failed to load player %d: %v", userID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	switch req.Action {
+	case ActionHit:
+		g.Hit()
+	case ActionStand:
+		g.Stand()
+	case ActionDouble:
+		if !hand.CanDouble() || !p.CanAfford(hand.Bet) || s.changeBalance(p, ledger.KindBet, -hand.Bet) != nil {
+			writeError(w, http.StatusConflict, "double is not allowed")
+			return
+		}
+		g.Double()
+	case ActionSplit:
+		if !g.CanSplit() || !p.CanAfford(hand.Bet) || s.changeBalance(p, ledger.KindBet, -hand.Bet) != nil {
+			writeError(w, http.StatusConflict, "split is not allowed")
+			return
+		}
+		g.Split()
+	default:
+		writeError(w, http.StatusBadRequest, "unknown action")
+		return
+	}
+	g.LastAction = time.Now()
+
+	// рука закрыта (стоп, перебор, удвоение, сплит тузов) — переходим дальше
+	var unlocked []achievement.ID
+	if hand := g.Current(); hand != nil && hand.IsStand && !g.NextHand() {
+		unlocked = s.settle(userID, g, p)
+	} else {
+		s.saveRound(g)
+	}
+
+	v := s.view(g, p)
+	v.Achievements = unlocked
+	writeJSON(w, http.StatusOK, v)
+}
+
+// settle доигрывает дилера, рассчитывает игрока и раскрывает сид раунда;
+// возвращает открытые в раунде достижения
+func (s *Server) settle(userID int64, g *game.State, p *player.Player) []achievement.ID {
+	g.Finish()
+	s.games.Delete(userID)
+
+	totalWin, wins, losses := 0, 0, 0
+	for _, hand := range g.Hands {
+		result, winAmount := g.HandResult(hand)
+		totalWin += winAmount
+
+		switch result {
+		case game.ResultBlackjack, game.ResultPlayerWin, game.ResultBonus:
+			wins++
+		case game.ResultDealerWin, game.ResultSurrender:
+			losses++
+		}
+	}
+
+	p.Settle(wins, losses)
+	if err := s.changeBalance(p, ledger.KindWin, totalWin); err != nil {
+		log.Printf("Failed to pay round #%d: %v", g.RoundID, err)
+	}
+	s.savePlayer(p)
+
+	if _, err := s.rounds.Finish(g.RoundID, g.Deck.Drawn(), g.Events); err != nil {
+		log.Printf("Failed to finish round: %v", err)
+	}
+
+	hands := make([]int, len(g.Hands))
+	for i := range hands {
+		hands[i] = i
+	}
+	unlocked, err := s.achievements.Unlock(userID, achievement.Earned(achievement.Round{Game: g, Hands: hands, WinStreak: p.WinStreak}))
+	if err != nil {
+		log.Printf("Failed to unlock achievements for %d: %v", userID, err)
+	}
+	return unlocked
+}
+
+func (s *Server) savePlayer(p *player.Player) {
+	if err := s.players.Save(p); err != nil {
+		log.Printf("Failed to save player %d: %v", p.UserID, err)
+	}
+}
+
+// saveRound сохраняет ход раунда, чтобы доиграть его после падения сервера (Restore)
+func (s *Server) saveRound(g *game.State) {
+	if err := s.rounds.Save(g.RoundID, g.Deck.Drawn(), g.Events); err != nil {
+		log.Printf("Failed to save round #%d: %v", g.RoundID, err)
+	}
+}
+
+// changeBalance меняет баланс через журнал и обновляет p.Balance
+func (s *Server) changeBalance(p *player.Player, kind ledger.Kind, amount int) error {
+	balance, err := s.ledger.Apply(p.UserID, ledger.Change{Kind: kind, Amount: amount})
+	if err != nil {
+		return err
+	}
+	p.Balance = balance
+	return nil
+}
+
+func (s *Server) view(g *game.State, p *player.Player) roundView {
+	rnd, err := s.rounds.Get(g.RoundID)
+	if err != nil {
+		log.Printf("Failed to get round %d: %v", g.RoundID, err)
+	}
+	return newRoundView(g, rnd, p)
+}
+
+// ============== ИГРОК ==============
+
+func (s *Server) handleBalance(w http.ResponseWriter, r *http.Request, userID int64) {
+	p, err := s.players.GetOrCreate(userID, s.cfg.StartBalance, s.cfg.DefaultBet)
+	if err != nil {
+		log.Printf("Failed to load player %d: %v", userID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	unlocks, err := s.achievements.List(userID)
+	if err != nil {
+		log.Printf("Failed to get achievements of %d: %v", userID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	achievements := make([]achievement.ID, 0, len(unlocks))
+	for _, u := range unlocks {
+		achievements = append(achievements, u.ID)
+	}
+
+	writeJSON(w, http.StatusOK, balanceView{
+		Balance:      p.Balance,
+		Games:        p.Games,
+		Wins:         p.Wins,
+		Losses:       p.Losses,
+		Draws:        p.Draws,
+		WinRate:      p.WinRate(),
+		Achievements: achievements,
+	})
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request, userID int64) {
+	limit := defaultHistoryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxHistoryLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxHistoryLimit))
+			return
+		}
+		limit = n
+	}
+
+	rounds, err := s.rounds.History(userID, limit)
+	if err != nil {
+		log.Printf("Failed to get history of %d: %v", userID, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	history := make([]historyView, 0, len(rounds))
+	for _, rnd := range rounds {
+		history = append(history, historyView{
+			RoundID:    rnd.ID,
+			Bet:        rnd.Bet,
+			SideBets:   rnd.SideBets,
+			Cards:      rnd.Cards,
+			Commitment: rnd.Commitment,
+			ServerSeed: rnd.ServerSeed,
+			ClientSeed: rnd.ClientSeed,
+			CreatedAt:  rnd.CreatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// handleLeaderboard открыт без токена, поэтому ID игроков не показывает
+func (s *Server) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.players.GetTopByBalance(10)
+	if err != nil {
+		log.Printf("Failed to get leaderboard: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	leaders := make([]leaderView, 0, len(stats))
+	for i, st := range stats {
+		leaders = append(leaders, leaderView{
+			Rank:    i + 1,
+			Balance: st.Balance,
+			Games:   st.Games,
+			WinRate: st.WinRate,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, leaders)
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"blackjack/internal/achievement"
+	"blackjack/internal/config"
+	"blackjack/internal/fair"
+	"blackjack/internal/game"
+	"blackjack/internal/ledger"
+	"blackjack/internal/player"
+	"blackjack/internal/round"
+	"blackjack/internal/token"
+)
+
+// Server — HTTP/JSON API поверх того же движка и кошельков, что и бот.
+// Игроки авторизуются токеном, выданным командой /token; игры ключуются
+// ID пользователя, как личный чат с ботом.
+type Server struct {
+	cfg     *config.Config
+	players player.Repository
+	rounds  round.Repository
+	tokens  token.Repository
+	ledger  ledger.Repository
+	games   *game.Manager
+
+	achievements achievement.Repository
+
+	// не дает начать два раунда одновременно
+	startMu sync.Mutex
+}
+
+func NewServer(cfg *config.Config, players player.Repository, rounds round.Repository, tokens token.Repository, ledger ledger.Repository, achievements achievement.Repository) *Server {
+	return &Server{
+		cfg:     cfg,
+		players: players,
+		rounds:  rounds,
+		tokens:  tokens,
+		ledger:  ledger,
+		games:   game.NewManager(),
+
+		achievements: achievements,
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/rounds", s.auth(s.handleStart))
+	mux.HandleFunc("GET /api/rounds/current", s.auth(s.handleCurrent))
+	mux.HandleFunc("POST /api/rounds/current/actions", s.auth(s.handleAction))
+	mux.HandleFunc("GET /api/balance", s.auth(s.handleBalance))
+	mux.HandleFunc("GET /api/history", s.auth(s.handleHistory))
+	mux.HandleFunc("GET /api/leaderboard", s.handleLeaderboard)
+
+	return mux
+}
+
+type authedHandler func(w http.ResponseWriter, r *http.Request, userID int64)
+
+// auth проверяет заголовок Authorization: Bearer <токен>
+func (s *Server) auth(next authedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tok, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || tok == "" {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		userID, err := s.tokens.UserID(tok)
+		if errors.Is(err, token.ErrNotFound) {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to check token: %v", err)
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		if p, err := s.players.Get(userID); err == nil && p.Banned {
+			writeError(w, http.StatusForbidden, "player is banned")
+			return
+		}
+
+		next(w, r, userID)
+	}
+}
+
+// RunSweeper рассчитывает игры, брошенные дольше cfg.AbandonedAfter
+func (s *Server) RunSweeper(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for userID, g := range s.games.Idle(s.cfg.AbandonedAfter) {
+				s.expire(userID, g)
+			}
+		}
+	}
+}
+
+// Close рассчитывает все незавершенные игры перед остановкой сервера:
+// оставшиеся руки закрываются так же, как по таймауту хода
+func (s *Server) Close() {
+	for userID, g := range s.games.All() {
+		s.expire(userID, g)
+	}
+}
+
+// Restore поднимает раунды API, прерванные падением сервера: состояние
+// восстанавливается повтором журнала; раунд, который не повторить, отменяется
+// с возвратом ставки
+func (s *Server) Restore() {
+	rounds, err := s.rounds.Active()
+	if err != nil {
+		log.Printf("Failed to load active rounds: %v", err)
+		return
+	}
+
+	for _, rnd := range rounds {
+		if !rnd.API {
+			continue
+		}
+
+		// журнал пишется сразу после раздачи; без него сервер упал раньше
+		if len(rnd.Events) == 0 {
+			s.cancelRound(rnd)
+			continue
+		}
+
+		g, err := game.Replay(fair.NewSource(rnd.ServerSeed, rnd.ClientSeed, rnd.ID), rnd.Events, nil)
+		if err != nil {
+			log.Printf("Failed to restore round #%d: %v", rnd.ID, err)
+			s.cancelRound(rnd)
+			continue
+		}
+
+		g.RoundID = rnd.ID
+		g.PlayerID = rnd.ChatID
+		g.BlackjackPays = s.cfg.BlackjackPays
+		g.LastAction = time.Now()
+		s.games.Set(rnd.ChatID, g)
+
+		// журнал кончается ходом, после которого рука могла закрыться
+		if hand := g.Current(); (hand == nil || hand.IsStand) && !g.NextHand() {
+			s.expire(rnd.ChatID, g)
+		}
+		log.Printf("Restored API round #%d of %d", rnd.ID, rnd.ChatID)
+	}
+}
+
+// cancelRound возвращает ставку раунда и закрывает его
+func (s *Server) cancelRound(rnd *round.Round) {
+	if _, err := s.ledger.Apply(rnd.ChatID, ledger.Change{Kind: ledger.KindRefund, Amount: rnd.Bet}); err != nil {
+		log.Printf("Failed to refund round #%d: %v", rnd.ID, err)
+		return
+	}
+	if _, err := s.rounds.Finish(rnd.ID, rnd.Cards, rnd.Events); err != nil {
+		log.Printf("Failed to close round #%d: %v", rnd.ID, err)
+	}
+	log.Printf("Cancelled API round #%d of %d, bet refunded", rnd.ID, rnd.ChatID)
+}
+
+func (s *Server) expire(userID int64, g *game.State) {
+	g.Lock()
+	defer g.Unlock()
+
+	if !g.IsActive {
+		s.games.Delete(userID)
+		return
+	}
+
+	p, err := s.players.GetOrCreate(userID, s.cfg.StartBalance, s.cfg.DefaultBet)
+	if err != nil {
+		log.Printf("Failed to load player %d: %v", userID, err)
+		return
+	}
+
+	for g.Current() != nil {
+		switch s.cfg.TimeoutAction {
+		case config.TimeoutSurrender:
+			g.Surrender()
+		case config.TimeoutForfeit:
+			g.Forfeit()
+		default:
+			g.Stand()
+		}
+		g.NextHand()
+	}
+
+	s.settle(userID, g, p)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"time"
+
+	"blackjack/internal/achievement"
+	"blackjack/internal/game"
+	"blackjack/internal/player"
+	"blackjack/internal/round"
+)
+
+type handView struct {
+	Box       int      `json:"box"`
+	Cards     []string `json:"cards"`
+	Score     int      `json:"score"`
+	Bet       int      `json:"bet"`
+	Stand     bool     `json:"stand"`
+	Bust      bool     `json:"bust"`
+	Blackjack bool     `json:"blackjack"`
+	Doubled   bool     `json:"doubled"`
+	Result    string   `json:"result,omitempty"`
+	Payout    int      `json:"payout,omitempty"`
+}
+
+type dealerView struct {
+	Cards  []string `json:"cards"`
+	Score  int      `json:"score"`
+	Hidden bool     `json:"hidden"`
+}
+
+type roundView struct {
+	RoundID     int64      `json:"round_id"`
+	Commitment  string     `json:"commitment"`
+	Active      bool       `json:"active"`
+	CurrentHand int        `json:"current_hand"`
+	Hands       []handView `json:"hands"`
+	Dealer      dealerView `json:"dealer"`
+	Actions     []string   `json:"actions,omitempty"`
+	Balance     int        `json:"balance"`
+
+	// после окончания раунда
+	TotalWin   int    `json:"total_win,omitempty"`
+	ServerSeed string `json:"server_seed,omitempty"`
+	ClientSeed string `json:"client_seed,omitempty"`
+
+	// достижения, открытые этим раундом
+	Achievements []achievement.ID `json:"achievements,omitempty"`
+}
+
+// newRoundView показывает игру; пока раунд идет, вторая карта дилера скрыта
+func newRoundView(g *game.State, rnd *round.Round, p *player.Player) roundView {
+	v := roundView{
+		RoundID:     g.RoundID,
+		Active:      g.IsActive,
+		CurrentHand: g.CurrentHand,
+		Balance:     p.Balance,
+	}
+	if rnd != nil {
+		v.Commitment = rnd.Commitment
+		if rnd.Status == round.StatusFinished {
+			v.ServerSeed = rnd.ServerSeed
+			v.ClientSeed = rnd.ClientSeed
+		}
+	}
+
+	for _, hand := range g.Hands {
+		hv := handView{
+			Box:       hand.Box,
+			Cards:     hand.Cards,
+			Score:     hand.Score(),
+			Bet:       hand.Bet,
+			Stand:     hand.IsStand,
+			Bust:      hand.IsBust,
+			Blackjack: hand.IsBlackjack(),
+			Doubled:   hand.IsDouble,
+		}
+		if !g.IsActive {
+			result, payout := g.HandResult(hand)
+			hv.Result = result.Name()
+			hv.Payout = payout
+			v.TotalWin += payout
+		}
+		v.Hands = append(v.Hands, hv)
+	}
+
+	if g.IsActive {
+		up := g.DealerCards[:1]
+		v.Dealer = dealerView{Cards: up, Score: game.CalculateScore(up), Hidden: true}
+		v.Actions = actions(g, p)
+	} else {
+		v.Dealer = dealerView{Cards: g.DealerCards, Score: g.DealerScore()}
+	}
+
+	return v
+}
+
+// actions — доступные сейчас действия для текущей руки
+func actions(g *game.State, p *player.Player) []string {
+	hand := g.Current()
+	if hand == nil {
+		return nil
+	}
+
+	acts := []string{ActionHit, ActionStand}
+	if hand.CanDouble() && p.CanAfford(hand.Bet) {
+		acts = append(acts, ActionDouble)
+	}
+	if g.CanSplit() && p.CanAfford(hand.Bet) {
+		acts = append(acts, ActionSplit)
+	}
+	return acts
+}
+
+type balanceView struct {
+	Balance int     `json:"balance"`
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Draws   int     `json:"draws"`
+	WinRate float64 `json:"win_rate"`
+
+	Achievements []achievement.ID `json:"achievements"`
+}
+
+type historyView struct {
+	RoundID    int64             `json:"round_id"`
+	Bet        int               `json:"bet"`
+	Cards      []string          `json:"cards"`
+	SideBets   []game.SideResult `json:"side_bets,omitempty"`
+	Commitment string            `json:"commitment"`
+	ServerSeed string            `json:"server_seed"`
+	ClientSeed string            `json:"client_seed"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+type leaderView struct {
+	Rank    int     `json:"rank"`
+	Balance int     `json:"balance"`
+	Games   int     `json:"games"`
+	WinRate float64 `json:"win_rate"`
+}
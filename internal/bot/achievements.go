@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"log"
+	"strings"
+
+	"blackjack/internal/achievement"
+	"blackjack/internal/game"
+	"blackjack/internal/i18n"
+)
+
+// ============== ДОСТИЖЕНИЯ ==============
+
+// unlockAchievements открывает достижения, заработанные игроком в раунде,
+// и возвращает новые
+func (h *Handler) unlockAchievements(userID int64, r achievement.Round) []achievement.ID {
+	ids, err := h.achievements.Unlock(userID, achievement.Earned(r))
+	if err != nil {
+		log.Printf("Failed to unlock achievements for %d: %v", userID, err)
+		return nil
+	}
+	return ids
+}
+
+// allHands — индексы всех рук игры, в личном чате они все принадлежат игроку
+func allHands(g *game.State) []int {
+	hands := make([]int, len(g.Hands))
+	for i := range hands {
+		hands[i] = i
+	}
+	return hands
+}
+
+func formatUnlocked(pr *i18n.Printer, ids []achievement.ID) string {
+	var sb strings.Builder
+	for _, id := range ids {
+		sb.WriteString("\n")
+		sb.WriteString(pr.T("achievement_unlocked", pr.T("ach_"+string(id))))
+	}
+	return sb.String()
+}
+
+// HandleAchievements показывает открытые и еще закрытые достижения
+func (h *Handler) HandleAchievements(chatID, userID int64, pr *i18n.Printer) {
+	unlocks, err := h.achievements.List(userID)
+	if err != nil {
+		log.Printf("Failed to get achievements of %d: %v", userID, err)
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	unlocked := make(map[achievement.ID]string, len(unlocks))
+	for _, u := range unlocks {
+		unlocked[u.ID] = u.UnlockedAt.Format("2006-01-02")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(pr.T("achievements_title", len(unlocks), len(achievement.All)))
+	for _, id := range achievement.All {
+		name, desc := pr.T("ach_"+string(id)), pr.T("ach_"+string(id)+"_desc")
+		if date, ok := unlocked[id]; ok {
+			sb.WriteString(pr.T("achievement_done", name, desc, date))
+		} else {
+			sb.WriteString(pr.T("achievement_locked", name, desc))
+		}
+	}
+
+	h.send(chatID, sb.String())
+}
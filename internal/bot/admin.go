@@ -0,0 +1,192 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackjack/internal/i18n"
+	"blackjack/internal/ledger"
+	"blackjack/internal/player"
+)
+
+// пауза между сообщениями рассылки — лимит Telegram около 30 сообщений в секунду
+const broadcastInterval = 50 * time.Millisecond
+
+// ============== АДМИНИСТРИРОВАНИЕ ==============
+
+// isBanned — забаненный игрок не может играть; администраторов бан не касается
+func (h *Handler) isBanned(userID int64) bool {
+	if h.cfg.IsAdmin(userID) {
+		return false
+	}
+	p, err := h.players.Get(userID)
+	return err == nil && p.Banned
+}
+
+// HandleAdmin — команды администратора; остальным /admin не отвечает.
+// Все изменения игроков идут через ledger и пишутся в лог с ID администратора.
+func (h *Handler) HandleAdmin(chatID, adminID int64, pr *i18n.Printer, text string) {
+	if !h.cfg.IsAdmin(adminID) {
+		return
+	}
+
+	parts := strings.Fields(text)
+	if len(parts) < 2 {
+		h.send(chatID, pr.T("admin_usage"))
+		return
+	}
+	sub, args := strings.ToLower(parts[1]), parts[2:]
+
+	if sub == "tournament" {
+		h.adminTournament(chatID, adminID, pr, args)
+		return
+	}
+
+	if sub == "broadcast" {
+		_, msg, _ := strings.Cut(text, parts[1])
+		h.adminBroadcast(chatID, adminID, pr, strings.TrimSpace(msg))
+		return
+	}
+
+	if len(args) == 0 {
+		h.send(chatID, pr.T("admin_usage"))
+		return
+	}
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		h.send(chatID, pr.T("admin_usage"))
+		return
+	}
+
+	switch sub {
+	case "grant", "set":
+		if len(args) < 2 {
+			h.send(chatID, pr.T("admin_usage"))
+			return
+		}
+		amount, err := strconv.Atoi(args[1])
+		if err != nil {
+			h.send(chatID, pr.T("admin_invalid_amount"))
+			return
+		}
+
+		var e *ledger.Entry
+		if sub == "grant" {
+			e, err = h.ledger.Grant(adminID, userID, amount)
+		} else {
+			e, err = h.ledger.Set(adminID, userID, amount)
+		}
+		if err != nil {
+			h.send(chatID, adminError(pr, userID, err))
+			return
+		}
+
+		log.Printf("Admin %d: %s %d to player %d, balance %d", adminID, e.Kind, e.Amount, userID, e.Balance)
+		h.send(chatID, pr.T("admin_balance", userID, e.Amount, e.Balance))
+
+	case "ban", "unban":
+		e, err := h.ledger.Ban(adminID, userID, sub == "ban")
+		if err != nil {
+			h.send(chatID, adminError(pr, userID, err))
+			return
+		}
+
+		log.Printf("Admin %d: %s player %d", adminID, e.Kind, userID)
+		if e.Kind == ledger.KindBan {
+			h.send(chatID, pr.T("admin_banned", userID))
+		} else {
+			h.send(chatID, pr.T("admin_unbanned", userID))
+		}
+
+	case "player":
+		h.adminPlayer(chatID, pr, userID)
+
+	default:
+		h.send(chatID, pr.T("admin_usage"))
+	}
+}
+
+func (h *Handler) adminPlayer(chatID int64, pr *i18n.Printer, userID int64) {
+	p, err := h.players.Get(userID)
+	if err != nil {
+		h.send(chatID, adminError(pr, userID, err))
+		return
+	}
+
+	banned := pr.T("admin_no")
+	if p.Banned {
+		banned = pr.T("admin_yes")
+	}
+	lang := p.Language
+	if lang == "" {
+		lang = "—"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(pr.T("admin_player", userID, p.Balance, p.Games, p.Wins, p.Losses, p.Draws, lang, banned))
+
+	entries, err := h.ledger.History(userID, 5)
+	if err != nil {
+		log.Printf("Failed to get ledger of %d: %v", userID, err)
+	}
+	if len(entries) > 0 {
+		sb.WriteString(pr.T("admin_ledger_title"))
+		for _, e := range entries {
+			sb.WriteString(pr.T("admin_ledger_line",
+				e.CreatedAt.Format("2006-01-02 15:04"), e.Kind, e.Amount, e.Balance, e.AdminID))
+		}
+	}
+
+	h.send(chatID, sb.String())
+}
+
+// adminBroadcast рассылает сообщение всем незабаненным игрокам в фоне
+// и сообщает администратору итог
+func (h *Handler) adminBroadcast(chatID, adminID int64, pr *i18n.Printer, text string) {
+	if text == "" {
+		h.send(chatID, pr.T("admin_broadcast_empty"))
+		return
+	}
+
+	ids, err := h.players.UserIDs()
+	if err != nil {
+		log.Printf("Failed to get players for broadcast: %v", err)
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	log.Printf("Admin %d: broadcast to %d players", adminID, len(ids))
+	h.send(chatID, pr.T("admin_broadcast_started", pr.N("players", len(ids))))
+
+	go func() {
+		sent, failed := 0, 0
+		for _, id := range ids {
+			if _, err := h.out.Send(id, text, nil); err != nil {
+				failed++
+			} else {
+				sent++
+			}
+			time.Sleep(broadcastInterval)
+		}
+
+		log.Printf("Admin %d: broadcast finished, %d sent, %d failed", adminID, sent, failed)
+		h.send(chatID, pr.T("admin_broadcast_done", sent, failed))
+	}()
+}
+
+func adminError(pr *i18n.Printer, userID int64, err error) string {
+	switch {
+	case errors.Is(err, ledger.ErrNotFound), errors.Is(err, player.ErrNotFound):
+		return pr.T("admin_not_found", userID)
+	case errors.Is(err, ledger.ErrNegative):
+		return pr.T("admin_negative")
+	case errors.Is(err, ledger.ErrAlreadyState):
+		return pr.T("admin_already", userID)
+	default:
+		log.Printf("Admin command failed: %v", err)
+		return pr.T("error")
+	}
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"strconv"
+	"strings"
+
+	"blackjack/internal/i18n"
+	"blackjack/internal/player"
+	"blackjack/internal/transport"
+)
+
+// ============== КЛАВИАТУРА ФИШЕК ==============
+
+// HandleBetSlip открывает клавиатуру фишек: ставка собирается кнопками, /bet 250 начинает с 250
+func (h *Handler) HandleBetSlip(chatID, userID int64, args []string) {
+	p, err := h.getPlayer(userID)
+	if err != nil {
+		h.send(chatID, printer(nil).T("error"))
+		return
+	}
+
+	pr := printer(p)
+	amount := 0
+	if len(args) > 0 {
+		if amount, err = strconv.Atoi(args[0]); err != nil || amount < 0 {
+			h.send(chatID, pr.T("invalid_bet", "/bet", h.cfg.DefaultBet))
+			return
+		}
+		if msg := h.slipError(pr, p, amount); msg != "" {
+			h.send(chatID, msg)
+			return
+		}
+	}
+	h.sendWithKeyboard(chatID, h.formatSlip(pr, p, amount), BetSlipKeyboard(pr, p, h.cfg.MaxBet, amount))
+}
+
+// handleSlipCallback обрабатывает кнопки клавиатуры фишек; false — кнопка не из нее
+func (h *Handler) handleSlipCallback(callback *transport.Action, p *player.Player, pr *i18n.Printer) bool {
+	action, value, _ := strings.Cut(callback.Data, ":")
+	if action != CallbackSlip && action != CallbackSlipDeal && action != CallbackSlipFav {
+		return false
+	}
+
+	// без суммы — открыть клавиатуру заново, например из кнопок конца игры
+	if value == "" {
+		h.answerCallback(callback.ID, "")
+		h.sendWithKeyboard(callback.ChatID, h.formatSlip(pr, p, 0), BetSlipKeyboard(pr, p, h.cfg.MaxBet, 0))
+		return true
+	}
+	amount, err := strconv.Atoi(value)
+	if err != nil || amount < 0 {
+		h.answerCallback(callback.ID, pr.T("error"))
+		return true
+	}
+
+	switch action {
+	case CallbackSlip:
+		if msg := h.slipError(pr, p, amount); msg != "" {
+			h.answerCallback(callback.ID, msg)
+			return true
+		}
+
+	case CallbackSlipFav:
+		added := p.ToggleFavorite(amount)
+		h.savePlayer(p)
+		if added {
+			h.answerCallback(callback.ID, pr.T("slip_fav_added", amount))
+		} else {
+			h.answerCallback(callback.ID, pr.T("slip_fav_removed", amount))
+		}
+		h.edit(callback.ChatID, callback.MessageID, h.formatSlip(pr, p, amount), BetSlipKeyboard(pr, p, h.cfg.MaxBet, amount))
+		return true
+
+	case CallbackSlipDeal:
+		if amount < h.cfg.MinBet {
+			h.answerCallback(callback.ID, pr.T("bet_range", h.cfg.MinBet, h.cfg.MaxBet))
+			return true
+		}
+		// клавиатура больше не нужна: ставка уходит в обычный /play
+		h.answerCallback(callback.ID, "")
+		h.edit(callback.ChatID, callback.MessageID, pr.T("slip_placed", amount), nil)
+		h.HandlePlay(callback.ChatID, p.UserID, []string{value})
+		return true
+	}
+
+	h.answerCallback(callback.ID, "")
+	h.edit(callback.ChatID, callback.MessageID, h.formatSlip(pr, p, amount), BetSlipKeyboard(pr, p, h.cfg.MaxBet, amount))
+	return true
+}
+
+// slipError — почему ставку нельзя собрать; пусто, если можно. Ставка меньше
+// минимальной допустима, пока ее собирают.
+func (h *Handler) slipError(pr *i18n.Printer, p *player.Player, amount int) string {
+	switch {
+	case amount > h.cfg.MaxBet:
+		return pr.T("bet_range", h.cfg.MinBet, h.cfg.MaxBet)
+	case amount > p.Balance:
+		return pr.T("no_funds", p.Balance)
+	}
+	return ""
+}
+
+func (h *Handler) formatSlip(pr *i18n.Printer, p *player.Player, amount int) string {
+	return pr.T("slip", amount, h.cfg.MinBet, h.cfg.MaxBet, p.Balance)
+}
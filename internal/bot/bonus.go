@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"blackjack/internal/i18n"
+	"blackjack/internal/ledger"
+	"blackjack/internal/player"
+)
+
+// ============== БОНУСЫ ==============
+
+// HandleBonus выдает ежедневный бонус; серия растет, если забирать его каждый день
+func (h *Handler) HandleBonus(chatID, userID int64, pr *i18n.Printer) {
+	e, streak, err := h.ledger.Bonus(userID, time.Now(), h.cfg.DailyBonus, h.cfg.BonusMaxStreak)
+	if errors.Is(err, ledger.ErrClaimed) {
+		h.send(chatID, pr.T("bonus_claimed", untilTomorrow(time.Now())))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to claim bonus for %d: %v", userID, err)
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	log.Printf("Player %d claimed daily bonus %d, streak %d", userID, e.Amount, streak)
+	h.send(chatID, pr.T("bonus", e.Amount, pr.N("days", streak), e.Balance))
+}
+
+// tryBailout пополняет баланс игроку, которому не хватает на минимальную ставку.
+// Если помощь была недавно, возвращает, сколько еще ждать.
+func (h *Handler) tryBailout(p *player.Player) (bool, time.Duration) {
+	e, err := h.ledger.Bailout(p.UserID, time.Now(), h.cfg.MinBet, h.cfg.BailoutAmount, h.cfg.BailoutCooldown)
+	switch {
+	case errors.Is(err, ledger.ErrNotBroke):
+		return false, 0
+	case errors.Is(err, ledger.ErrClaimed):
+		return false, time.Until(p.BailoutAt.Add(h.cfg.BailoutCooldown)).Round(time.Minute)
+	case err != nil:
+		log.Printf("Failed to bail out %d: %v", p.UserID, err)
+		return false, 0
+	}
+
+	log.Printf("Player %d bailed out, balance %d", p.UserID, e.Balance)
+	p.Balance = e.Balance
+	return true, 0
+}
+
+// untilTomorrow — сколько осталось до следующего дня по UTC
+func untilTomorrow(now time.Time) time.Duration {
+	now = now.UTC()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return tomorrow.Sub(now).Round(time.Minute)
+}
@@ -1,56 +1,153 @@
 package bot
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
+	"blackjack/internal/achievement"
 	"blackjack/internal/config"
+	"blackjack/internal/fair"
 	"blackjack/internal/game"
+	"blackjack/internal/i18n"
+	"blackjack/internal/ledger"
+	"blackjack/internal/live"
 	"blackjack/internal/player"
-
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"blackjack/internal/render"
+	"blackjack/internal/round"
+	"blackjack/internal/table"
+	"blackjack/internal/token"
+	"blackjack/internal/tournament"
+	"blackjack/internal/transport"
 )
 
+// maxCaption — сколько символов Telegram принимает в подписи к картинке
+const maxCaption = 1024
+
+// Handler — ядро бота: команды, игры и столы поверх любого транспорта
 type Handler struct {
-	bot     *tgbotapi.BotAPI
+	out     transport.Transport
 	cfg     *config.Config
 	players player.Repository
+	rounds  round.Repository
+	tokens  token.Repository
+	ledger  ledger.Repository
 	games   *game.Manager
+	tables  *table.Manager
+
+	achievements achievement.Repository
+	tournaments  tournament.Repository
+
+	// таблицы турниров обновляются и турнир завершается по одному
+	tournamentMu sync.Mutex
+
+	// трансляция для зрителей; nil — выключена
+	live *live.Hub
 }
 
-func NewHandler(bot *tgbotapi.BotAPI, cfg *config.Config, repo player.Repository) *Handler {
+func NewHandler(out transport.Transport, cfg *config.Config, repo player.Repository, rounds round.Repository, tokens token.Repository, ledger ledger.Repository, achievements achievement.Repository, tournaments tournament.Repository, hub *live.Hub) *Handler {
 	return &Handler{
-		bot:     bot,
+		out:     out,
 		cfg:     cfg,
 		players: repo,
+		rounds:  rounds,
+		tokens:  tokens,
+		ledger:  ledger,
 		games:   game.NewManager(),
+		tables:  table.NewManager(),
+
+		achievements: achievements,
+		tournaments:  tournaments,
+		live:         hub,
 	}
 }
 
 // ============== ВСПОМОГАТЕЛЬНЫЕ МЕТОДЫ ==============
 
 func (h *Handler) send(chatID int64, text string) {
-	if _, err := h.bot.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+	if _, err := h.out.Send(chatID, text, nil); err != nil {
 		log.Printf("Failed to send message: %v", err)
 	}
 }
 
-func (h *Handler) sendWithKeyboard(chatID int64, text string, kb tgbotapi.InlineKeyboardMarkup) {
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ReplyMarkup = kb
-	if _, err := h.bot.Send(msg); err != nil {
+// sendWithKeyboard возвращает ID отправленного сообщения (0 при ошибке)
+func (h *Handler) sendWithKeyboard(chatID int64, text string, kb transport.Keyboard) int {
+	id, err := h.out.Send(chatID, text, kb)
+	if err != nil {
 		log.Printf("Failed to send message: %v", err)
+		return 0
+	}
+	return id
+}
+
+// edit заменяет сообщение; kb == nil убирает кнопки
+func (h *Handler) edit(chatID int64, messageID int, text string, kb transport.Keyboard) {
+	if err := h.out.Edit(chatID, messageID, text, kb); err != nil {
+		log.Printf("Failed to edit message: %v", err)
+	}
+}
+
+// sendRound отправляет сообщение об игре: картинкой стола с text в подписи,
+// если игрок выбрал картинки, иначе текстом; возвращает ID сообщения (0 при ошибке)
+func (h *Handler) sendRound(chatID int64, g *game.State, text string, kb transport.Keyboard) int {
+	if photo := h.roundPhoto(g, text); photo != nil {
+		id, err := h.out.SendPhoto(chatID, photo, text, kb)
+		if err == nil {
+			return id
+		}
+		log.Printf("Failed to send photo: %v", err)
+	}
+	return h.sendWithKeyboard(chatID, text, kb)
+}
+
+// editRound заменяет сообщение об игре, отправленное через sendRound
+func (h *Handler) editRound(chatID int64, messageID int, g *game.State, text string, kb transport.Keyboard) {
+	if photo := h.roundPhoto(g, text); photo != nil {
+		err := h.out.EditPhoto(chatID, messageID, photo, text, kb)
+		if err == nil {
+			return
+		}
+		// сообщение могло уйти текстом, если картинка не отправилась
+		log.Printf("Failed to edit photo: %v", err)
+	}
+	h.edit(chatID, messageID, text, kb)
+}
+
+// roundPhoto — картинка стола для игры g или nil, если игра идет текстом
+// или текст не помещается в подпись
+func (h *Handler) roundPhoto(g *game.State, text string) []byte {
+	if !g.Images || utf8.RuneCountInString(text) > maxCaption {
+		return nil
+	}
+
+	photo, err := render.Table(g)
+	if err != nil {
+		log.Printf("Failed to render round #%d: %v", g.RoundID, err)
+		return nil
 	}
+	return photo
+}
+
+// sendGame отправляет состояние игры с кнопками и запускает отсчет времени на ход
+func (h *Handler) sendGame(chatID int64, g *game.State, text string, kb transport.Keyboard) {
+	g.MessageID = h.sendRound(chatID, g, text, kb)
+	g.LastAction = time.Now()
+	h.armTurnTimer(chatID, g)
 }
 
 func (h *Handler) answerCallback(id, text string) {
-	h.bot.Request(tgbotapi.NewCallback(id, text))
+	if err := h.out.Answer(id, text); err != nil {
+		log.Printf("Failed to answer action: %v", err)
+	}
 }
 
-func (h *Handler) getPlayer(chatID int64) (*player.Player, error) {
-	return h.players.GetOrCreate(chatID, h.cfg.StartBalance, h.cfg.DefaultBet)
+func (h *Handler) getPlayer(userID int64) (*player.Player, error) {
+	return h.players.GetOrCreate(userID, h.cfg.StartBalance, h.cfg.DefaultBet)
 }
 
 func (h *Handler) savePlayer(p *player.Player) {
@@ -59,52 +156,163 @@ func (h *Handler) savePlayer(p *player.Player) {
 	}
 }
 
+// changeBalance меняет основной баланс игрока через журнал одной транзакцией
+// и обновляет p.Balance; при нехватке фишек — ledger.ErrNegative
+func (h *Handler) changeBalance(p *player.Player, changes ...ledger.Change) error {
+	balance, err := h.ledger.Apply(p.UserID, changes...)
+	if err != nil {
+		return err
+	}
+	p.Balance = balance
+	return nil
+}
+
+// changeGameBalance меняет баланс посреди игры g там, откуда он взят:
+// основной — через журнал, турнирный — в памяти с сохранением
+func (h *Handler) changeGameBalance(g *game.State, p *player.Player, kind ledger.Kind, amount int) error {
+	if g.TournamentID == 0 {
+		return h.changeBalance(p, ledger.Change{Kind: kind, Amount: amount})
+	}
+
+	if p.Balance+amount < 0 {
+		return ledger.ErrNegative
+	}
+	p.Balance += amount
+	h.saveGamePlayer(g, p)
+	return nil
+}
+
+// printer — язык игрока; без игрока или до выбора языка — язык по умолчанию
+func printer(p *player.Player) *i18n.Printer {
+	if p == nil {
+		return i18n.New(i18n.Default)
+	}
+	return i18n.New(i18n.Lang(p.Language))
+}
+
+// userPrinter возвращает язык автора апдейта. Пока игрок не выбрал язык
+// через /lang, он берется из настроек Telegram и запоминается.
+func (h *Handler) userPrinter(u transport.User) *i18n.Printer {
+	p, err := h.getPlayer(u.ID)
+	if err != nil {
+		return printer(nil)
+	}
+
+	if p.Language == "" {
+		p.Language = string(i18n.Match(u.LanguageCode))
+		h.savePlayer(p)
+	}
+	return printer(p)
+}
+
+// startRound берет заранее опубликованный коммитмент и фиксирует сид клиента
+func (h *Handler) startRound(chatID int64, p *player.Player, bet int) (*round.Round, error) {
+	return round.Begin(h.rounds, chatID, false, p.Seed(), bet)
+}
+
+// revealRound сохраняет сданные карты, раскрывает сид сервера
+// и публикует коммитмент следующего раунда
+func (h *Handler) revealRound(chatID int64, pr *i18n.Printer, g *game.State) string {
+	rnd, err := h.rounds.Finish(g.RoundID, g.Deck.Drawn(), g.Events)
+	if err != nil {
+		log.Printf("Failed to finish round: %v", err)
+		return ""
+	}
+
+	text := pr.T("reveal", rnd.ID, rnd.ServerSeed, rnd.ID)
+
+	next, err := h.rounds.Next(chatID)
+	if err != nil {
+		log.Printf("Failed to prepare next round: %v", err)
+		return text
+	}
+
+	return text + pr.T("reveal_next", next.Commitment)
+}
+
 // ============== ФОРМАТИРОВАНИЕ ==============
 
-func formatHandStatus(hand *game.Hand, index int, total int) string {
+func formatHandStatus(pr *i18n.Printer, g *game.State, index int) string {
+	hand := g.Hands[index]
+
 	prefix := "🎴"
-	if total > 1 {
-		prefix = fmt.Sprintf("🎴 Рука %d:", index+1)
+	switch {
+	case g.HasMultipleBoxes() && g.BoxHands(hand.Box) > 1:
+		prefix = pr.T("hand_box_hand", hand.Box+1, handInBox(g, index)+1)
+	case g.HasMultipleBoxes():
+		prefix = pr.T("hand_box", hand.Box+1)
+	case len(g.Hands) > 1:
+		prefix = pr.T("hand_n", index+1)
 	}
 
 	status := ""
 	if hand.IsBust {
 		status = " 💥"
+	} else if hand.IsBlackjack() {
+		status = " 🎰"
 	} else if hand.IsStand {
 		status = " ✋"
 	}
 
-	return fmt.Sprintf("%s %v (%d)%s", prefix, hand.Cards, hand.Score(), status)
+	bet := ""
+	switch {
+	case hand.Free > 0:
+		bet = pr.T("hand_bet_free", hand.Real(), hand.Free)
+	case g.HasMultipleBoxes():
+		bet = fmt.Sprintf(" · %d", hand.Bet)
+	}
+
+	return fmt.Sprintf("%s %v (%d)%s%s", prefix, hand.Cards, hand.Score(), bet, status)
 }
 
-func (h *Handler) formatGameStatus(g *game.State, showDealer bool) string {
+// номер руки внутри бокса
+func handInBox(g *game.State, index int) int {
+	n := 0
+	for i := 0; i < index; i++ {
+		if g.Hands[i].Box == g.Hands[index].Box {
+			n++
+		}
+	}
+	return n
+}
+
+func formatBets(bets []int) string {
+	parts := make([]string, len(bets))
+	for i, b := range bets {
+		parts[i] = strconv.Itoa(b)
+	}
+	return strings.Join(parts, " + ")
+}
+
+func (h *Handler) formatGameStatus(pr *i18n.Printer, g *game.State, showDealer bool) string {
 	var sb strings.Builder
 
 	// Показываем все руки
-	for i, hand := range g.Hands {
+	for i := range g.Hands {
 		if i == g.CurrentHand && !g.AllHandsComplete() {
 			sb.WriteString("👉 ") // Текущая рука
 		}
-		sb.WriteString(formatHandStatus(hand, i, len(g.Hands)))
+		sb.WriteString(formatHandStatus(pr, g, i))
 		sb.WriteString("\n")
 	}
 
 	// Дилер
 	if showDealer {
-		sb.WriteString(fmt.Sprintf("🃏 Дилер: %v (%d)", g.DealerCards, g.DealerScore()))
+		sb.WriteString(pr.T("dealer", g.DealerCards, g.DealerScore()))
 	} else {
-		sb.WriteString(fmt.Sprintf("🃏 Дилер: [%s, ?]", g.DealerCards[0]))
+		sb.WriteString(pr.T("dealer_hidden", g.DealerCards[0]))
 	}
 
 	return sb.String()
 }
 
-func (h *Handler) formatGameEnd(g *game.State, p *player.Player, results []string, totalWin int) string {
+func (h *Handler) formatGameEnd(chatID int64, g *game.State, p *player.Player, results []string, totalWin int) string {
+	pr := printer(p)
 	var sb strings.Builder
 
 	// Руки игрока с результатами
-	for i, hand := range g.Hands {
-		sb.WriteString(formatHandStatus(hand, i, len(g.Hands)))
+	for i := range g.Hands {
+		sb.WriteString(formatHandStatus(pr, g, i))
 		if i < len(results) {
 			sb.WriteString(" — ")
 			sb.WriteString(results[i])
@@ -112,12 +320,23 @@ func (h *Handler) formatGameEnd(g *game.State, p *player.Player, results []strin
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString(fmt.Sprintf("🃏 Дилер: %v (%d)\n", g.DealerCards, g.DealerScore()))
+	sb.WriteString(pr.T("dealer", g.DealerCards, g.DealerScore()))
+	if game.IsBlackjack(g.DealerCards) {
+		sb.WriteString(" — BLACKJACK!")
+	}
+	sb.WriteString("\n")
+
+	// побочные ставки рассчитаны при раздаче, здесь — для сводки
+	if side := formatSideResults(pr, g); side != "" {
+		sb.WriteString(strings.TrimPrefix(side, "\n"))
+		sb.WriteString("\n")
+	}
 
 	if totalWin > 0 {
-		sb.WriteString(fmt.Sprintf("\n💰 Выигрыш: +%d", totalWin))
+		sb.WriteString(pr.T("total_win", pr.N("chips", totalWin)))
 	}
-	sb.WriteString(fmt.Sprintf("\n💵 Баланс: %d", p.Balance))
+	sb.WriteString(pr.T("end_balance", p.Balance))
+	sb.WriteString(h.revealRound(chatID, pr, g))
 
 	return sb.String()
 }
@@ -129,196 +348,707 @@ func (h *Handler) getKeyboardOptions(g *game.State, p *player.Player) GameKeyboa
 	}
 
 	return GameKeyboardOptions{
-		CanDouble: hand.CanDouble() && p.CanAfford(hand.Bet),
-		CanSplit:  hand.CanSplit() && p.CanAfford(hand.Bet) && len(g.Hands) < 4,
+		CanDouble:    hand.CanDouble() && p.CanAfford(g.DoubleCost()),
+		CanSplit:     g.CanSplit() && p.CanAfford(g.SplitCost()),
+		FreeDouble:   hand.CanDouble() && g.DoubleCost() == 0,
+		FreeSplit:    g.CanSplit() && g.SplitCost() == 0,
+		CanSurrender: g.CanSurrender(),
+		CanSwitch:    g.CanSwitch(),
+		Rescue:       hand.IsDouble && g.CanSurrender(),
 	}
 }
 
 // ============== ОБРАБОТЧИКИ КОМАНД ==============
 
-func (h *Handler) HandleStart(chatID int64) {
-	p, err := h.getPlayer(chatID)
+func (h *Handler) HandleStart(chatID, userID int64) {
+	p, err := h.getPlayer(userID)
 	if err != nil {
-		h.send(chatID, "❌ Ошибка. Попробуйте позже.")
+		h.send(chatID, printer(nil).T("error_later"))
 		return
 	}
 
-	h.send(chatID, fmt.Sprintf(
-		"🎰 Добро пожаловать в Blackjack!\n\n"+
-			"💵 Баланс: %d\n\n"+
-			"/play <ставка> — играть\n"+
-			"/balance — статистика\n"+
-			"/top — топ игроков\n"+
-			"/help — правила",
-		p.Balance))
+	pr := printer(p)
+	h.send(chatID, pr.T("start", pr.N("chips", p.Balance)))
 }
 
-func (h *Handler) HandleHelp(chatID int64) {
-	h.send(chatID,
-		"📖 Правила Blackjack:\n\n"+
-			"🎯 Цель: набрать 21 или больше дилера\n\n"+
-			"📊 Очки: 2-10 номинал, J/Q/K = 10, A = 11 или 1\n\n"+
-			"🎮 Действия:\n"+
-			"• Hit — взять карту\n"+
-			"• Stand — остановиться\n"+
-			"• Double — удвоить ставку\n"+
-			"• Split — разделить пару\n\n"+
-			"✂️ Split: при двух одинаковых картах можно разделить на две руки. Каждая рука играет отдельно.\n\n"+
-			"🎰 Blackjack платит x2.5")
+func (h *Handler) HandleHelp(chatID int64, pr *i18n.Printer) {
+	timeout := ""
+	if h.cfg.ActionTimeout > 0 {
+		timeout = pr.T("help_timeout", h.cfg.ActionTimeout)
+	}
+
+	var side strings.Builder
+	for _, hand := range game.PokerHands {
+		side.WriteString(pr.T("help_side_line", pr.T("poker_"+string(hand)), h.cfg.SidePaytable[hand]))
+	}
+
+	var pairs strings.Builder
+	for _, hand := range game.PairHands {
+		pairs.WriteString(pr.T("help_side_line", pr.T("pair_"+string(hand)), h.cfg.PairsPaytable[hand]))
+	}
+
+	h.send(chatID, pr.T("help", h.cfg.MaxBoxes, h.cfg.BlackjackPays,
+		pr.T("help_side", side.String())+pr.T("help_pairs", pairs.String())+pr.T("help_spanish")+pr.T("help_freebet")+pr.T("help_switch"), timeout))
 }
 
-func (h *Handler) HandleBalance(chatID int64) {
-	p, err := h.getPlayer(chatID)
+func (h *Handler) HandleBalance(chatID, userID int64) {
+	p, err := h.getPlayer(userID)
 	if err != nil {
-		h.send(chatID, "❌ Ошибка")
+		h.send(chatID, printer(nil).T("error"))
 		return
 	}
 
-	h.send(chatID, fmt.Sprintf(
-		"💰 Баланс: %d\n\n"+
-			"📊 Статистика:\n"+
-			"🎮 Игр: %d\n"+
-			"✅ Побед: %d (%.1f%%)\n"+
-			"❌ Поражений: %d\n"+
-			"🤝 Ничьих: %d",
-		p.Balance, p.Games, p.Wins, p.WinRate(), p.Losses, p.Draws))
+	pr := printer(p)
+	text := pr.T("balance",
+		pr.N("chips", p.Balance), pr.N("games", p.Games), p.Wins, p.WinRate(), p.Losses, p.Draws)
+
+	if unlocks, err := h.achievements.List(userID); err == nil {
+		text += pr.T("balance_achievements", len(unlocks), len(achievement.All))
+	}
+	h.send(chatID, text)
 }
 
-func (h *Handler) HandleTop(chatID int64) {
+func (h *Handler) HandleTop(chatID int64, pr *i18n.Printer) {
 	stats, err := h.players.GetTopByBalance(10)
 	if err != nil {
-		h.send(chatID, "❌ Ошибка")
+		h.send(chatID, pr.T("error"))
 		return
 	}
 
 	if len(stats) == 0 {
-		h.send(chatID, "🏆 Пока никто не играл!")
+		h.send(chatID, pr.T("top_empty"))
 		return
 	}
 
 	var sb strings.Builder
-	sb.WriteString("🏆 Топ игроков:\n\n")
+	sb.WriteString(pr.T("top_title"))
 
-	medals := []string{"🥇", "🥈", "🥉"}
 	for i, s := range stats {
-		medal := fmt.Sprintf("%d.", i+1)
-		if i < 3 {
-			medal = medals[i]
-		}
-		sb.WriteString(fmt.Sprintf("%s %d 💰 | %d игр (%.0f%%)\n",
-			medal, s.Balance, s.Games, s.WinRate))
+		sb.WriteString(pr.T("top_line", placeMark(i), s.Balance, pr.N("games", s.Games), s.WinRate))
 	}
 
 	h.send(chatID, sb.String())
 }
 
-func (h *Handler) HandlePlay(chatID int64, args []string) {
-	p, err := h.getPlayer(chatID)
+// placeMark — медаль за первые три места, дальше номер
+func placeMark(i int) string {
+	medals := []string{"🥇", "🥈", "🥉"}
+	if i < len(medals) {
+		return medals[i]
+	}
+	return fmt.Sprintf("%d.", i+1)
+}
+
+// HandleLang показывает выбор языка или сразу ставит указанный: /lang en
+func (h *Handler) HandleLang(chatID, userID int64, args []string) {
+	if len(args) == 0 {
+		p, _ := h.getPlayer(userID)
+		h.sendWithKeyboard(chatID, printer(p).T("lang_choose"), LanguageKeyboard())
+		return
+	}
+
+	h.send(chatID, h.setLanguage(userID, args[0]))
+}
+
+// setLanguage сохраняет язык игрока и возвращает подтверждение на новом языке
+func (h *Handler) setLanguage(userID int64, code string) string {
+	p, err := h.getPlayer(userID)
+	if err != nil {
+		return printer(nil).T("error")
+	}
+
+	lang, ok := i18n.Parse(code)
+	if !ok {
+		return printer(p).T("lang_unknown")
+	}
+
+	p.Language = string(lang)
+	h.savePlayer(p)
+	return printer(p).T("lang_set")
+}
+
+// HandleImages переключает показ игры картинкой и текстом: /images, /images on, /images off
+func (h *Handler) HandleImages(chatID, userID int64, args []string) {
+	p, err := h.getPlayer(userID)
 	if err != nil {
-		h.send(chatID, "❌ Ошибка")
+		h.send(chatID, printer(nil).T("error"))
 		return
 	}
 
-	bet := h.cfg.DefaultBet
+	pr := printer(p)
+	on := !p.CardImages
 	if len(args) > 0 {
-		if b, err := strconv.Atoi(args[0]); err == nil && b > 0 {
-			bet = b
-		} else {
-			h.send(chatID, fmt.Sprintf("❌ Неверная ставка. Пример: /play %d", h.cfg.DefaultBet))
+		switch strings.ToLower(args[0]) {
+		case "on":
+			on = true
+		case "off":
+			on = false
+		default:
+			h.send(chatID, pr.T("images_usage"))
+			return
+		}
+	}
+
+	p.CardImages = on
+	h.savePlayer(p)
+
+	// идущая игра переключается со следующего хода
+	if g := h.games.Get(chatID); g != nil {
+		g.Lock()
+		g.Images = on
+		g.Unlock()
+	}
+
+	if on {
+		h.send(chatID, pr.T("images_on"))
+	} else {
+		h.send(chatID, pr.T("images_off"))
+	}
+}
+
+func (h *Handler) HandlePlay(chatID, userID int64, args []string) {
+	p, err := h.getPlayer(userID)
+	if err != nil {
+		h.send(chatID, printer(nil).T("error"))
+		return
+	}
+
+	pr := printer(p)
+	if g := h.games.Get(chatID); g != nil && g.TournamentID != 0 {
+		h.send(chatID, pr.T("tournament_round_active"))
+		return
+	}
+
+	bets, o, total, ok := h.parseBets(chatID, pr, "/play", args)
+	if !ok {
+		return
+	}
+
+	// банкроту без идущей игры помогаем, чтобы он не застрял навсегда
+	if !p.CanAfford(total) && h.games.Get(chatID) == nil {
+		if ok, wait := h.tryBailout(p); ok {
+			h.send(chatID, pr.T("bailout", p.Balance, h.cfg.BailoutCooldown))
+		} else if wait > 0 {
+			h.send(chatID, pr.T("no_funds_bailout", p.Balance, wait))
 			return
 		}
 	}
 
-	if bet < h.cfg.MinBet || bet > h.cfg.MaxBet {
-		h.send(chatID, fmt.Sprintf("❌ Ставка от %d до %d", h.cfg.MinBet, h.cfg.MaxBet))
+	if !p.CanAfford(total) {
+		h.send(chatID, pr.T("no_funds", p.Balance))
 		return
 	}
 
-	if !p.PlaceBet(bet) {
-		h.send(chatID, fmt.Sprintf("❌ Недостаточно средств! Баланс: %d", p.Balance))
+	// списываем до раунда: баланс в базе мог измениться после загрузки игрока;
+	// побочные ставки — отдельной записью журнала в той же транзакции
+	side := o.sidePerBox() * len(bets)
+	err = h.changeBalance(p,
+		ledger.Change{Kind: ledger.KindBet, Amount: side - total},
+		ledger.Change{Kind: ledger.KindSideBet, Amount: -side})
+	if err != nil {
+		if !errors.Is(err, ledger.ErrNegative) {
+			log.Printf("Failed to charge bet: %v", err)
+		}
+		h.send(chatID, pr.T("no_funds", p.Balance))
 		return
 	}
 
-	g := game.NewState(bet)
+	rnd, err := h.startRound(chatID, p, total)
+	if err != nil {
+		log.Printf("Failed to start round: %v", err)
+		if err := h.changeBalance(p, ledger.Change{Kind: ledger.KindRefund, Amount: total}); err != nil {
+			log.Printf("Failed to refund bet: %v", err)
+		}
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	p.LastBet = bets[len(bets)-1]
+	h.savePlayer(p)
+
+	h.dealGame(chatID, p, rnd, bets, o, 0, "")
+}
+
+// betOptions — вариант правил и побочные ставки на каждый бокс: 21+3 и Perfect Pairs
+type betOptions struct {
+	variant game.Variant
+	plus3   int
+	pairs   int
+}
+
+func (o betOptions) sidePerBox() int {
+	return o.plus3 + o.pairs
+}
+
+// parseBets читает вариант правил (spanish), ставки по боксам (без них — ставка
+// по умолчанию) и побочные ставки на каждый бокс (side=10 для 21+3, pairs=10 для
+// Perfect Pairs), проверяет лимиты и возвращает общую сумму; при ошибке сам отвечает игроку
+func (h *Handler) parseBets(chatID int64, pr *i18n.Printer, cmd string, args []string) ([]int, betOptions, int, bool) {
+	var o betOptions
+	var bets []int
+	for i, arg := range args {
+		arg = strings.ToLower(arg)
+		if v, ok := game.ParseVariant(arg); ok && i == 0 {
+			o.variant = v
+			continue
+		}
+
+		var target *int
+		if value, ok := strings.CutPrefix(arg, "side="); ok {
+			arg, target = value, &o.plus3
+		} else if value, ok := strings.CutPrefix(arg, "pairs="); ok {
+			arg, target = value, &o.pairs
+		}
+
+		b, err := strconv.Atoi(arg)
+		if err != nil || b <= 0 || (target != nil && *target > 0) {
+			h.send(chatID, pr.T("invalid_bet", cmd, h.cfg.DefaultBet))
+			return nil, betOptions{}, 0, false
+		}
+
+		if target != nil {
+			*target = b
+		} else {
+			bets = append(bets, b)
+		}
+	}
+
+	if len(bets) > h.cfg.MaxBoxes {
+		h.send(chatID, pr.T("too_many_boxes", pr.N("boxes", h.cfg.MaxBoxes)))
+		return nil, betOptions{}, 0, false
+	}
+	if len(bets) == 0 {
+		bets = []int{h.cfg.DefaultBet}
+	}
+
+	// в Switch два бокса с равными ставками: /play switch 100 ставит 100 на оба
+	if o.variant == game.VariantSwitch {
+		if len(bets) == 1 {
+			bets = append(bets, bets[0])
+		}
+		if len(bets) != 2 || bets[0] != bets[1] {
+			h.send(chatID, pr.T("switch_bets"))
+			return nil, betOptions{}, 0, false
+		}
+	}
+
+	total := 0
+	for _, bet := range bets {
+		if bet < h.cfg.MinBet || bet > h.cfg.MaxBet {
+			h.send(chatID, pr.T("bet_range", h.cfg.MinBet, h.cfg.MaxBet))
+			return nil, betOptions{}, 0, false
+		}
+		total += bet
+	}
+
+	for _, bet := range []int{o.plus3, o.pairs} {
+		if bet > 0 && (bet < h.cfg.MinBet || bet > h.cfg.MaxBet) {
+			h.send(chatID, pr.T("side_range", h.cfg.MinBet, h.cfg.MaxBet))
+			return nil, betOptions{}, 0, false
+		}
+	}
+	return bets, o, total + o.sidePerBox()*len(bets), true
+}
+
+// dealGame раздает оплаченный раунд в личном чате и сразу рассчитывает побочные ставки.
+// tournamentID — турнир, с фишек которого списаны ставки (0 — основной баланс);
+// note — строка над игрой.
+func (h *Handler) dealGame(chatID int64, p *player.Player, rnd *round.Round, bets []int, o betOptions, tournamentID int64, note string) {
+	pr := printer(p)
+
+	g := game.NewVariantState(bets, o.variant, fair.NewSource(rnd.ServerSeed, rnd.ClientSeed, rnd.ID))
+	g.RoundID = rnd.ID
+	g.PlayerID = p.UserID
+	g.TournamentID = tournamentID
+	g.SideBet = o.plus3
+	g.PairsBet = o.pairs
+	g.BlackjackPays = h.cfg.BlackjackPays
+	g.Images = p.CardImages
 	h.games.Set(chatID, g)
+	h.live.Track(chatID, g, nil)
 
-	hand := g.Current()
+	if g.Variant != game.VariantClassic {
+		note += pr.T("variant_round", pr.T("variant_"+g.Variant.Name()))
+	}
+	h.settleSideBets(g, p)
 
-	// Проверка блэкджеков
-	playerBJ := hand.IsBlackjack()
-	dealerBJ := game.IsBlackjack(g.DealerCards)
+	// Блэкджек у дилера или у всех боксов — раунд решен сразу
+	if game.IsBlackjack(g.DealerCards) || g.AllHandsComplete() {
+		text := h.settleGame(chatID, g, p)
+		h.sendRound(chatID, g, note+text, h.endGameKeyboard(pr, g))
+		return
+	}
 
-	if playerBJ || dealerBJ {
-		g.IsActive = false
+	if len(g.SideResults) > 0 {
+		h.saveGamePlayer(g, p)
+	}
 
-		if playerBJ && dealerBJ {
-			p.AddDraw(bet)
-			h.savePlayer(p)
-			h.sendWithKeyboard(chatID,
-				fmt.Sprintf("🎴 Вы: %v — BLACKJACK!\n🃏 Дилер: %v — BLACKJACK!\n\n🤝 Ничья!\n💵 Баланс: %d",
-					hand.Cards, g.DealerCards, p.Balance),
-				EndGameKeyboard(p.LastBet))
-			return
+	opts := h.getKeyboardOptions(g, p)
+	h.sendGame(chatID, g,
+		note+pr.T("round_started", rnd.ID, rnd.Commitment, formatBets(bets), p.Balance, h.formatGameStatus(pr, g, false))+formatSideResults(pr, g),
+		GameKeyboard(pr, opts))
+}
+
+// settleSideBets рассчитывает побочные ставки каждого бокса сразу после раздачи
+// и записывает итоги в раунд. Выигрыш со ставкой зачисляется на баланс игры:
+// основной — через журнал, турнирный сохраняется вместе с игрой. Проигрыш уже списан.
+func (h *Handler) settleSideBets(g *game.State, p *player.Player) {
+	for box := range g.InitialBets {
+		if g.SideBet > 0 {
+			cards := g.SideCards(box)
+			hand := game.ThreeCardPoker(cards)
+			g.SideResults = append(g.SideResults, game.SideResult{
+				Kind: game.SidePlus3, Box: box, Cards: cards, Hand: string(hand),
+				Bet: g.SideBet, Win: h.cfg.SidePaytable.Payout(hand, g.SideBet),
+			})
+		}
+		if g.PairsBet > 0 {
+			cards := g.PairCards(box)
+			hand := game.PerfectPairs(cards)
+			g.SideResults = append(g.SideResults, game.SideResult{
+				Kind: game.SidePairs, Box: box, Cards: cards, Hand: string(hand),
+				Bet: g.PairsBet, Win: h.cfg.PairsPaytable.Payout(hand, g.PairsBet),
+			})
 		}
+	}
+	if len(g.SideResults) == 0 {
+		return
+	}
 
-		if playerBJ {
-			winAmount := int(float64(bet) * h.cfg.BlackjackPays)
-			p.AddWin(winAmount)
-			h.savePlayer(p)
-			h.sendWithKeyboard(chatID,
-				fmt.Sprintf("🎴 Вы: %v\n\n🎰 BLACKJACK! 🎰\n\n💰 +%d (x%.1f)\n💵 Баланс: %d",
-					hand.Cards, winAmount, h.cfg.BlackjackPays, p.Balance),
-				EndGameKeyboard(p.LastBet))
-			return
+	if err := h.rounds.SaveSideBets(g.RoundID, g.SideResults); err != nil {
+		log.Printf("Failed to save side bets of round #%d: %v", g.RoundID, err)
+	}
+
+	win := 0
+	for _, r := range g.SideResults {
+		win += r.Win
+	}
+	if win == 0 {
+		return
+	}
+
+	if err := h.changeGameBalance(g, p, ledger.KindSideBet, win); err != nil {
+		log.Printf("Failed to pay side bets of round #%d: %v", g.RoundID, err)
+	}
+}
+
+// resultReason поясняет итог руки, решенный правилом дома: " — 5-card Charlie"
+func resultReason(pr *i18n.Printer, g *game.State, hand *game.Hand) string {
+	switch g.ResultReason(hand) {
+	case game.ReasonCharlie:
+		return pr.T("reason_charlie", g.Rules.Charlie)
+	case game.ReasonPlayer21Wins:
+		return pr.T("reason_21wins")
+	case game.ReasonDealerTies:
+		return pr.T("reason_ties")
+	}
+	return ""
+}
+
+// formatRules — правила дома через запятую
+func formatRules(pr *i18n.Printer, r game.Rules) string {
+	var names []string
+	if r.Charlie > 0 {
+		names = append(names, pr.T("rule_charlie", r.Charlie))
+	}
+	if r.Player21Wins {
+		names = append(names, pr.T("rule_21wins"))
+	}
+	if r.DealerWinsTies {
+		names = append(names, pr.T("rule_ties"))
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatSideResults — строки с итогами побочных ставок раунда
+func formatSideResults(pr *i18n.Printer, g *game.State) string {
+	var sb strings.Builder
+	for _, r := range g.SideResults {
+		label, prefix := "21+3", "poker_"
+		if r.Kind == game.SidePairs {
+			label, prefix = "Perfect Pairs", "pair_"
+		}
+		if g.HasMultipleBoxes() {
+			label += pr.T("side_box", r.Box+1)
+		}
+
+		if r.Win == 0 {
+			sb.WriteString(pr.T("side_loss", label, r.Cards))
+		} else {
+			sb.WriteString(pr.T("side_win", label, r.Cards, pr.T(prefix+r.Hand), r.Win))
 		}
+	}
+	return sb.String()
+}
+
+func (h *Handler) HandleSeed(chatID, userID int64, args []string) {
+	p, err := h.getPlayer(userID)
+	if err != nil {
+		h.send(chatID, printer(nil).T("error"))
+		return
+	}
 
-		p.AddLoss()
+	pr := printer(p)
+	if len(args) > 0 {
+		seed := strings.Join(args, " ")
+		if len(seed) > 64 {
+			h.send(chatID, pr.T("seed_too_long"))
+			return
+		}
+		p.ClientSeed = seed
 		h.savePlayer(p)
-		h.sendWithKeyboard(chatID,
-			fmt.Sprintf("🎴 Вы: %v (%d)\n🃏 Дилер: %v\n\n🎰 BLACKJACK у дилера!\n💵 Баланс: %d",
-				hand.Cards, hand.Score(), g.DealerCards, p.Balance),
-			EndGameKeyboard(p.LastBet))
+	}
+
+	next, err := h.rounds.Next(chatID)
+	if err != nil {
+		log.Printf("Failed to prepare next round: %v", err)
+		h.send(chatID, pr.T("error"))
 		return
 	}
 
-	h.savePlayer(p)
+	h.send(chatID, pr.T("seed_info", p.Seed(), next.Commitment))
+}
 
-	opts := h.getKeyboardOptions(g, p)
-	h.sendWithKeyboard(chatID,
-		fmt.Sprintf("💰 Ставка: %d | Баланс: %d\n\n%s",
-			bet, p.Balance, h.formatGameStatus(g, false)),
-		GameKeyboard(opts))
+// HandleToken выдает новый токен HTTP API; прежний перестает действовать
+func (h *Handler) HandleToken(chatID, userID int64) {
+	p, err := h.getPlayer(userID)
+	if err != nil {
+		h.send(chatID, printer(nil).T("error"))
+		return
+	}
+
+	pr := printer(p)
+	tok, err := h.tokens.Issue(userID)
+	if err != nil {
+		log.Printf("Failed to issue token: %v", err)
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	h.send(chatID, pr.T("token_issued", tok))
+}
+
+// HandleLive дает ссылку на трансляцию раундов этого чата
+func (h *Handler) HandleLive(chatID int64, pr *i18n.Printer) {
+	if h.live == nil {
+		h.send(chatID, pr.T("live_disabled"))
+		return
+	}
+
+	base := h.cfg.LiveURL
+	if rest, ok := strings.CutPrefix(base, "http"); ok {
+		base = "ws" + rest
+	}
+	h.send(chatID, pr.T("live_link", fmt.Sprintf("%s/live/%d?key=%s", base, chatID, h.live.Key(chatID))))
+}
+
+// finishedRound находит завершенный раунд по аргументу команды
+func (h *Handler) finishedRound(chatID int64, pr *i18n.Printer, cmd string, args []string) *round.Round {
+	if len(args) == 0 {
+		h.send(chatID, pr.T("round_missing", cmd))
+		return nil
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil || id <= 0 {
+		h.send(chatID, pr.T("round_invalid"))
+		return nil
+	}
+
+	rnd, err := h.rounds.Get(id)
+	if err != nil {
+		h.send(chatID, pr.T("round_not_found"))
+		return nil
+	}
+
+	if rnd.Status != round.StatusFinished {
+		h.send(chatID, pr.T("round_not_finished"))
+		return nil
+	}
+	return rnd
+}
+
+func (h *Handler) HandleVerify(chatID int64, pr *i18n.Printer, args []string) {
+	rnd := h.finishedRound(chatID, pr, "/verify", args)
+	if rnd == nil {
+		return
+	}
+
+	if err := fair.Verify(rnd.ServerSeed, rnd.ClientSeed, rnd.ID, rnd.Commitment, rnd.Variant(), rnd.Cards); err != nil {
+		h.send(chatID, pr.T("verify_failed", rnd.ID, err))
+		return
+	}
+
+	h.send(chatID, pr.T("verify_ok", rnd.ID, rnd.Commitment, rnd.ServerSeed, rnd.ClientSeed, rnd.Cards))
+}
+
+func (h *Handler) HandleReplay(chatID int64, pr *i18n.Printer, args []string) {
+	rnd := h.finishedRound(chatID, pr, "/replay", args)
+	if rnd == nil {
+		return
+	}
+
+	if len(rnd.Events) == 0 {
+		h.send(chatID, pr.T("replay_no_log", rnd.ID))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(pr.T("replay_title", rnd.ID))
+
+	step := 0
+	var dealer []string
+	src := fair.NewSource(rnd.ServerSeed, rnd.ClientSeed, rnd.ID)
+	_, err := game.Replay(src, rnd.Events, func(ev game.Event, g *game.State) {
+		if ev.Hand == game.DealerHand && ev.Card != "" {
+			dealer = append(dealer, ev.Card)
+		}
+
+		line := formatReplayStep(pr, ev, g, dealer)
+		if line == "" {
+			return
+		}
+		step++
+		sb.WriteString(fmt.Sprintf("\n%d. %s", step, line))
+	})
+	if err != nil {
+		h.send(chatID, pr.T("replay_failed", rnd.ID, err))
+		return
+	}
+
+	h.send(chatID, sb.String())
+}
+
+// formatReplayStep описывает шаг повтора; dealer — карты дилера на этот момент.
+// События начальной раздачи сворачиваются в одну строку на второй карте дилера.
+func formatReplayStep(pr *i18n.Printer, ev game.Event, g *game.State, dealer []string) string {
+	handName := func(i int) string {
+		if len(g.Hands) > 1 {
+			return pr.T("replay_hand_n", i+1)
+		}
+		return pr.T("replay_hand")
+	}
+
+	switch ev.Type {
+	case game.EventBet:
+		return pr.T("replay_bet", ev.Bet)
+	case game.EventDeal:
+		if ev.Hand == game.DealerHand && len(dealer) == 2 {
+			hands := make([]string, 0, len(g.InitialBets))
+			for i := range g.InitialBets {
+				hands = append(hands, fmt.Sprintf("%v (%d)", g.Hands[i].Cards, g.Hands[i].Score()))
+			}
+			return pr.T("replay_deal", strings.Join(hands, ", "), dealer)
+		}
+		if ev.Hand != game.DealerHand && g.Hands[ev.Hand].FromSplit && len(g.Hands[ev.Hand].Cards) == 2 {
+			hand := g.Hands[ev.Hand]
+			return pr.T("replay_card", handName(ev.Hand), ev.Card, hand.Cards, hand.Score())
+		}
+		return ""
+	case game.EventHit:
+		hand := g.Hands[ev.Hand]
+		return pr.T("replay_hit", handName(ev.Hand), ev.Card, hand.Cards, hand.Score())
+	case game.EventStand:
+		return pr.T("replay_stand", handName(ev.Hand))
+	case game.EventDouble:
+		hand := g.Hands[ev.Hand]
+		return pr.T("replay_double", handName(ev.Hand), ev.Card, hand.Cards, hand.Score())
+	case game.EventSplit:
+		return pr.T("replay_split")
+	case game.EventSwitch:
+		return pr.T("replay_switch", g.Hands[0].Cards, g.Hands[1].Cards)
+	case game.EventSurrender:
+		return pr.T("replay_surrender", handName(ev.Hand))
+	case game.EventForfeit:
+		return pr.T("replay_forfeit", handName(ev.Hand))
+	case game.EventFinish:
+		return pr.T("replay_reveal", dealer, game.CalculateScore(dealer))
+	case game.EventDealerDraw:
+		return pr.T("replay_dealer_draw", ev.Card, dealer, game.CalculateScore(dealer))
+	}
+	return ""
 }
 
 // ============== ОБРАБОТЧИКИ CALLBACK ==============
 
-func (h *Handler) HandleCallback(callback *tgbotapi.CallbackQuery) {
-	chatID := callback.Message.Chat.ID
+func (h *Handler) HandleCallback(callback *transport.Action) {
+	chatID := callback.ChatID
 	data := callback.Data
 
-	p, err := h.getPlayer(chatID)
+	if h.isBanned(callback.From.ID) {
+		h.answerCallback(callback.ID, h.userPrinter(callback.From).T("banned"))
+		return
+	}
+
+	if code, ok := strings.CutPrefix(data, CallbackLang+":"); ok {
+		h.answerCallback(callback.ID, "")
+		h.edit(chatID, callback.MessageID, h.setLanguage(callback.From.ID, code), nil)
+		return
+	}
+
+	if callback.Group {
+		h.handleTableCallback(callback)
+		return
+	}
+
+	h.userPrinter(callback.From)
+	p, err := h.getPlayer(callback.From.ID)
 	if err != nil {
-		h.answerCallback(callback.ID, "Ошибка")
+		h.answerCallback(callback.ID, printer(nil).T("error"))
+		return
+	}
+	pr := printer(p)
+
+	if bets, ok := strings.CutPrefix(data, CallbackPlayAgain+":"); ok {
+		h.answerCallback(callback.ID, "")
+		h.HandlePlay(chatID, callback.From.ID, strings.Split(bets, ","))
+		return
+	}
+
+	if bets, ok := strings.CutPrefix(data, CallbackTournamentPlay+":"); ok {
+		h.answerCallback(callback.ID, "")
+		h.handleTournamentPlay(chatID, callback.From.ID, pr, strings.Split(bets, ","))
+		return
+	}
+
+	if h.handleSlipCallback(callback, p, pr) {
 		return
 	}
 
 	switch data {
 	case CallbackPlayAgain:
 		h.answerCallback(callback.ID, "")
-		h.HandlePlay(chatID, []string{strconv.Itoa(p.LastBet)})
+		h.HandlePlay(chatID, callback.From.ID, []string{strconv.Itoa(p.LastBet)})
 		return
 
 	case CallbackBalance:
-		h.answerCallback(callback.ID, fmt.Sprintf("💵 %d", p.Balance))
+		h.answerCallback(callback.ID, pr.T("balance_toast", p.Balance))
 		return
 	}
 
 	g := h.games.Get(chatID)
-	if g == nil || !g.IsActive {
-		h.answerCallback(callback.ID, "Игра не активна")
+	if g == nil {
+		h.answerCallback(callback.ID, pr.T("game_inactive"))
 		return
 	}
 
+	// ход и таймаут не должны менять игру одновременно
+	g.Lock()
+	defer g.Unlock()
+
+	if !g.IsActive {
+		h.answerCallback(callback.ID, pr.T("game_inactive"))
+		return
+	}
+
+	// в турнирной игре ставки идут с турнирных фишек
+	if g.TournamentID != 0 {
+		if p, err = h.gamePlayer(g); err != nil {
+			h.answerCallback(callback.ID, pr.T("error"))
+			return
+		}
+	}
+
 	switch data {
 	case CallbackHit:
 		h.handleHit(chatID, g, p)
@@ -328,24 +1058,31 @@ func (h *Handler) HandleCallback(callback *tgbotapi.CallbackQuery) {
 		h.handleDouble(chatID, g, p)
 	case CallbackSplit:
 		h.handleSplit(chatID, g, p)
+	case CallbackSurrender:
+		h.handleSurrender(chatID, g, p)
+	case CallbackSwitch:
+		h.handleSwitch(chatID, g, p)
 	}
 
 	h.answerCallback(callback.ID, "")
 }
 
 func (h *Handler) handleHit(chatID int64, g *game.State, p *player.Player) {
+	pr := printer(p)
 	g.Hit()
 	hand := g.Current()
 
-	if hand.IsBust {
+	// перебор или Charlie — рука закрыта
+	if hand.IsStand {
 		// Переход к следующей руке или завершение
 		if g.NextHand() {
 			// Есть ещё руки
+			text := pr.T("stand_next", g.CurrentHand+1, h.formatGameStatus(pr, g, false))
+			if hand.IsBust {
+				text = pr.T("bust_next", g.CurrentHand, h.formatGameStatus(pr, g, false))
+			}
 			opts := h.getKeyboardOptions(g, p)
-			h.sendWithKeyboard(chatID,
-				fmt.Sprintf("💥 Перебор на руке %d!\n\n%s",
-					g.CurrentHand, h.formatGameStatus(g, false)),
-				GameKeyboard(opts))
+			h.sendGame(chatID, g, text, GameKeyboard(pr, opts))
 		} else {
 			// Все руки сыграны
 			h.finishGame(chatID, g, p)
@@ -354,86 +1091,170 @@ func (h *Handler) handleHit(chatID int64, g *game.State, p *player.Player) {
 	}
 
 	opts := h.getKeyboardOptions(g, p)
-	h.sendWithKeyboard(chatID, h.formatGameStatus(g, false), GameKeyboard(opts))
+	h.sendGame(chatID, g, h.formatGameStatus(pr, g, false), GameKeyboard(pr, opts))
 }
 
 func (h *Handler) handleStand(chatID int64, g *game.State, p *player.Player) {
+	pr := printer(p)
 	g.Stand()
 
 	if g.NextHand() {
 		// Переход к следующей руке
 		opts := h.getKeyboardOptions(g, p)
-		h.sendWithKeyboard(chatID,
-			fmt.Sprintf("✋ Стоим. Переход к руке %d\n\n%s",
-				g.CurrentHand+1, h.formatGameStatus(g, false)),
-			GameKeyboard(opts))
+		h.sendGame(chatID, g,
+			pr.T("stand_next", g.CurrentHand+1, h.formatGameStatus(pr, g, false)),
+			GameKeyboard(pr, opts))
 	} else {
 		h.finishGame(chatID, g, p)
 	}
 }
 
 func (h *Handler) handleDouble(chatID int64, g *game.State, p *player.Player) {
+	pr := printer(p)
 	hand := g.Current()
 	if hand == nil || !hand.CanDouble() {
 		return
 	}
 
-	if !p.CanAfford(hand.Bet) {
-		h.send(chatID, "❌ Недостаточно средств для удвоения")
+	cost := g.DoubleCost()
+	if !p.CanAfford(cost) {
+		h.send(chatID, pr.T("double_no_funds"))
 		return
 	}
 
-	p.Balance -= hand.Bet
+	if err := h.changeGameBalance(g, p, ledger.KindBet, -cost); err != nil {
+		if !errors.Is(err, ledger.ErrNegative) {
+			log.Printf("Failed to charge double: %v", err)
+		}
+		h.send(chatID, pr.T("double_no_funds"))
+		return
+	}
 	g.Double()
 
+	// в испанском варианте после удвоения можно остановиться или спасти ставку
+	if !hand.IsStand {
+		opts := h.getKeyboardOptions(g, p)
+		h.sendGame(chatID, g, pr.T("double_rescue", h.formatGameStatus(pr, g, false)), GameKeyboard(pr, opts))
+		return
+	}
+
 	if g.NextHand() {
 		status := "✋"
 		if hand.IsBust {
 			status = "💥"
 		}
 		opts := h.getKeyboardOptions(g, p)
-		h.sendWithKeyboard(chatID,
-			fmt.Sprintf("💰 Удвоено! %s Переход к руке %d\n\n%s",
-				status, g.CurrentHand+1, h.formatGameStatus(g, false)),
-			GameKeyboard(opts))
+		h.sendGame(chatID, g,
+			pr.T("double_next", status, g.CurrentHand+1, h.formatGameStatus(pr, g, false)),
+			GameKeyboard(pr, opts))
+	} else {
+		h.finishGame(chatID, g, p)
+	}
+}
+
+// handleSurrender — поздняя сдача или спасение удвоения в испанском варианте;
+// возврат половины ставки руки начисляется при расчете
+func (h *Handler) handleSurrender(chatID int64, g *game.State, p *player.Player) {
+	pr := printer(p)
+	if !g.CanSurrender() {
+		return
+	}
+	g.Surrender()
+
+	if g.NextHand() {
+		opts := h.getKeyboardOptions(g, p)
+		h.sendGame(chatID, g,
+			pr.T("surrender_next", g.CurrentHand+1, h.formatGameStatus(pr, g, false)),
+			GameKeyboard(pr, opts))
 	} else {
 		h.finishGame(chatID, g, p)
 	}
 }
 
+// handleSwitch меняет вторые карты боксов в Blackjack Switch
+func (h *Handler) handleSwitch(chatID int64, g *game.State, p *player.Player) {
+	pr := printer(p)
+	if !g.SwitchCards() {
+		return
+	}
+
+	// обмен мог собрать блэкджек на обоих боксах
+	if g.AllHandsComplete() {
+		h.finishGame(chatID, g, p)
+		return
+	}
+
+	opts := h.getKeyboardOptions(g, p)
+	h.sendGame(chatID, g, pr.T("switch_done", h.formatGameStatus(pr, g, false)), GameKeyboard(pr, opts))
+}
+
 func (h *Handler) handleSplit(chatID int64, g *game.State, p *player.Player) {
+	pr := printer(p)
 	hand := g.Current()
-	if hand == nil || !hand.CanSplit() {
+	if hand == nil || !g.CanSplit() {
 		return
 	}
 
-	if !p.CanAfford(hand.Bet) {
-		h.send(chatID, "❌ Недостаточно средств для сплита")
+	cost := g.SplitCost()
+	if !p.CanAfford(cost) {
+		h.send(chatID, pr.T("split_no_funds"))
 		return
 	}
 
-	// Списываем ставку для новой руки
-	p.Balance -= hand.Bet
-	h.savePlayer(p)
+	// Списываем ставку для новой руки; в Free Bet она может быть бесплатной
+	if err := h.changeGameBalance(g, p, ledger.KindBet, -cost); err != nil {
+		if !errors.Is(err, ledger.ErrNegative) {
+			log.Printf("Failed to charge split: %v", err)
+		}
+		h.send(chatID, pr.T("split_no_funds"))
+		return
+	}
 
 	g.Split()
 
-	// Если сплит тузов — сразу завершаем
+	// Сплит тузов — по одной карте, руки сразу закрыты
 	if hand.SplitAces {
-		h.send(chatID, "✂️ Сплит тузов! По одной карте на каждую руку.")
+		if g.NextHand() {
+			opts := h.getKeyboardOptions(g, p)
+			h.sendGame(chatID, g,
+				pr.T("split_aces")+"\n\n"+h.formatGameStatus(pr, g, false),
+				GameKeyboard(pr, opts))
+			return
+		}
+		h.send(chatID, pr.T("split_aces"))
 		h.finishGame(chatID, g, p)
 		return
 	}
 
 	opts := h.getKeyboardOptions(g, p)
-	h.sendWithKeyboard(chatID,
-		fmt.Sprintf("✂️ Сплит! Теперь у вас %d руки.\n💰 Общая ставка: %d | Баланс: %d\n\n%s",
-			len(g.Hands), g.TotalBet(), p.Balance, h.formatGameStatus(g, false)),
-		GameKeyboard(opts))
+	h.sendGame(chatID, g,
+		pr.T("split_done", pr.N("hands", len(g.Hands)), g.TotalBet(), p.Balance, h.formatGameStatus(pr, g, false)),
+		GameKeyboard(pr, opts))
 }
 
 func (h *Handler) finishGame(chatID int64, g *game.State, p *player.Player) {
+	text := h.settleGame(chatID, g, p)
+	h.sendRound(chatID, g, text, h.endGameKeyboard(printer(p), g))
+}
+
+// endGameKeyboard — повтор раунда; в турнире — следующий раунд, пока он есть
+func (h *Handler) endGameKeyboard(pr *i18n.Printer, g *game.State) transport.Keyboard {
+	if g.TournamentID == 0 {
+		return EndGameKeyboard(pr, g)
+	}
+	if !h.canPlayTournament(g.TournamentID, g.PlayerID) {
+		return nil
+	}
+	return TournamentKeyboard(pr, g)
+}
+
+// settleGame доигрывает дилера, рассчитывает игрока, освобождает игру
+// и возвращает итоговый текст
+func (h *Handler) settleGame(chatID int64, g *game.State, p *player.Player) string {
+	pr := printer(p)
 	g.Finish()
+	h.games.Delete(chatID)
+	h.live.Settle(chatID, g, nil)
 
 	var results []string
 	totalWin := 0
@@ -445,44 +1266,54 @@ func (h *Handler) finishGame(chatID int64, g *game.State, p *player.Player) {
 		result, winAmount := g.HandResult(hand)
 
 		switch result {
+		case game.ResultBlackjack:
+			results = append(results, pr.T("result_blackjack", g.BlackjackPayout()))
+			totalWin += winAmount
+			wins++
 		case game.ResultPlayerWin:
-			results = append(results, "🎉 Победа!")
+			results = append(results, pr.T("result_win")+resultReason(pr, g, hand))
+			totalWin += winAmount
+			wins++
+		case game.ResultBonus:
+			results = append(results, pr.T("result_bonus", winAmount))
 			totalWin += winAmount
 			wins++
 		case game.ResultDealerWin:
-			results = append(results, "😔 Проигрыш")
+			results = append(results, pr.T("result_loss")+resultReason(pr, g, hand))
 			losses++
 		case game.ResultPush:
-			results = append(results, "🤝 Ничья")
+			results = append(results, pr.T("result_push"))
 			totalWin += winAmount
 			draws++
+		case game.ResultSurrender:
+			results = append(results, pr.T("result_surrender", winAmount))
+			totalWin += winAmount
+			losses++
 		}
 	}
 
-	// Обновляем баланс и статистику
-	p.Balance += totalWin
-
-	// Считаем как одну игру, но записываем все победы/поражения
-	if wins > losses {
-		p.Wins++
-	} else if losses > wins {
-		p.Losses++
+	// Обновляем баланс и статистику; турнирный раунд идет в зачет турнира
+	p.Settle(wins, losses)
+	standing := ""
+	if g.TournamentID != 0 {
+		p.Balance += totalWin
+		standing = h.finishTournamentRound(g, p)
 	} else {
-		p.Draws++
+		if err := h.changeBalance(p, ledger.Change{Kind: ledger.KindWin, Amount: totalWin}); err != nil {
+			log.Printf("Failed to pay round #%d: %v", g.RoundID, err)
+		}
+		h.savePlayer(p)
 	}
-	p.Games++
 
-	h.savePlayer(p)
+	unlocked := h.unlockAchievements(p.UserID, achievement.Round{Game: g, Hands: allHands(g), WinStreak: p.WinStreak})
 
-	h.sendWithKeyboard(chatID,
-		h.formatGameEnd(g, p, results, totalWin),
-		EndGameKeyboard(g.InitialBet))
+	return h.formatGameEnd(chatID, g, p, results, totalWin) + standing + formatUnlocked(pr, unlocked)
 }
 
 // ============== ОБРАБОТЧИК СООБЩЕНИЙ ==============
 
-func (h *Handler) HandleMessage(msg *tgbotapi.Message) {
-	chatID := msg.Chat.ID
+func (h *Handler) HandleMessage(msg *transport.Message) {
+	chatID := msg.ChatID
 	text := msg.Text
 	parts := strings.Fields(text)
 
@@ -490,19 +1321,61 @@ func (h *Handler) HandleMessage(msg *tgbotapi.Message) {
 		return
 	}
 
-	cmd := strings.ToLower(parts[0])
+	// в группах команды приходят как /play@BotName
+	cmd, _, _ := strings.Cut(strings.ToLower(parts[0]), "@")
 	args := parts[1:]
 
+	userID := msg.From.ID
+
+	if h.isBanned(userID) {
+		// в группах молчим, чтобы не засорять чат
+		if !msg.Group {
+			h.send(chatID, h.userPrinter(msg.From).T("banned"))
+		}
+		return
+	}
+
+	if msg.Group {
+		h.HandleGroupMessage(chatID, msg.From, cmd, args)
+		return
+	}
+
+	pr := h.userPrinter(msg.From)
+
 	switch {
 	case cmd == "/start":
-		h.HandleStart(chatID)
+		h.HandleStart(chatID, userID)
 	case cmd == "/help":
-		h.HandleHelp(chatID)
+		h.HandleHelp(chatID, pr)
 	case cmd == "/play":
-		h.HandlePlay(chatID, args)
+		h.HandlePlay(chatID, userID, args)
+	case cmd == "/bet":
+		h.HandleBetSlip(chatID, userID, args)
 	case cmd == "/balance":
-		h.HandleBalance(chatID)
+		h.HandleBalance(chatID, userID)
 	case cmd == "/top":
-		h.HandleTop(chatID)
+		h.HandleTop(chatID, pr)
+	case cmd == "/seed":
+		h.HandleSeed(chatID, userID, args)
+	case cmd == "/verify":
+		h.HandleVerify(chatID, pr, args)
+	case cmd == "/replay":
+		h.HandleReplay(chatID, pr, args)
+	case cmd == "/lang":
+		h.HandleLang(chatID, userID, args)
+	case cmd == "/images":
+		h.HandleImages(chatID, userID, args)
+	case cmd == "/token":
+		h.HandleToken(chatID, userID)
+	case cmd == "/live":
+		h.HandleLive(chatID, pr)
+	case cmd == "/bonus":
+		h.HandleBonus(chatID, userID, pr)
+	case cmd == "/achievements":
+		h.HandleAchievements(chatID, userID, pr)
+	case cmd == "/tournament":
+		h.HandleTournament(chatID, msg.From, pr, args, false)
+	case cmd == "/admin":
+		h.HandleAdmin(chatID, userID, pr, text)
 	}
 }
@@ -2,8 +2,14 @@ package bot
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"blackjack/internal/game"
+	"blackjack/internal/i18n"
+	"blackjack/internal/player"
+	"blackjack/internal/transport"
 )
 
 const (
@@ -11,39 +17,188 @@ const (
 	CallbackStand     = "stand"
 	CallbackDouble    = "double"
 	CallbackSplit     = "split"
+	CallbackSurrender = "surrender"
+	CallbackSwitch    = "switch"
 	CallbackPlayAgain = "play_again"
 	CallbackBalance   = "balance"
+
+	// CallbackTournamentPlay — следующий турнирный раунд: "tournament_play:100,50"
+	CallbackTournamentPlay = "tournament_play"
+
+	CallbackTableBet   = "table_bet"
+	CallbackTableLeave = "table_leave"
+
+	// CallbackLang — префикс выбора языка: "lang:en"
+	CallbackLang = "lang"
+
+	// кнопки клавиатуры фишек; сумма ставки после нажатия едет в данных: "slip:450",
+	// без суммы клавиатура открывается заново
+	CallbackSlip     = "slip"
+	CallbackSlipDeal = "slip_deal"
+	CallbackSlipFav  = "slip_fav"
 )
 
+// Номиналы фишек клавиатуры ставки
+var chips = []int{10, 25, 100, 500, 1000}
+
 type GameKeyboardOptions struct {
-	CanDouble bool
-	CanSplit  bool
+	CanDouble    bool
+	CanSplit     bool
+	CanSurrender bool
+	CanSwitch    bool
+
+	// удвоение и сплит за бесплатные фишки Free Bet
+	FreeDouble bool
+	FreeSplit  bool
+
+	// рука удвоена в испанском варианте: остаются «стоп» и спасение
+	Rescue bool
 }
 
-func GameKeyboard(opts GameKeyboardOptions) tgbotapi.InlineKeyboardMarkup {
-	row := []tgbotapi.InlineKeyboardButton{
-		tgbotapi.NewInlineKeyboardButtonData("👊 Hit", CallbackHit),
-		tgbotapi.NewInlineKeyboardButtonData("✋ Stand", CallbackStand),
+func GameKeyboard(pr *i18n.Printer, opts GameKeyboardOptions) transport.Keyboard {
+	if opts.Rescue {
+		return transport.Keyboard{{
+			{Text: pr.T("btn_stand"), Data: CallbackStand},
+			{Text: pr.T("btn_rescue"), Data: CallbackSurrender},
+		}}
+	}
+
+	row := []transport.Button{
+		{Text: pr.T("btn_hit"), Data: CallbackHit},
+		{Text: pr.T("btn_stand"), Data: CallbackStand},
 	}
 
 	if opts.CanDouble {
-		row = append(row, tgbotapi.NewInlineKeyboardButtonData("💰 Double", CallbackDouble))
+		text := pr.T("btn_double")
+		if opts.FreeDouble {
+			text = pr.T("btn_double_free")
+		}
+		row = append(row, transport.Button{Text: text, Data: CallbackDouble})
 	}
 	if opts.CanSplit {
-		row = append(row, tgbotapi.NewInlineKeyboardButtonData("✂️ Split", CallbackSplit))
+		text := pr.T("btn_split")
+		if opts.FreeSplit {
+			text = pr.T("btn_split_free")
+		}
+		row = append(row, transport.Button{Text: text, Data: CallbackSplit})
+	}
+	if opts.CanSurrender {
+		row = append(row, transport.Button{Text: pr.T("btn_surrender"), Data: CallbackSurrender})
 	}
 
-	return tgbotapi.NewInlineKeyboardMarkup(row)
+	kb := transport.Keyboard{row}
+	if opts.CanSwitch {
+		kb = append(kb, []transport.Button{{Text: pr.T("btn_switch"), Data: CallbackSwitch}})
+	}
+	return kb
 }
 
-func EndGameKeyboard(lastBet int) tgbotapi.InlineKeyboardMarkup {
-	return tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("🔄 Ещё (%d)", lastBet),
-				CallbackPlayAgain,
-			),
-			tgbotapi.NewInlineKeyboardButtonData("💵 Баланс", CallbackBalance),
-		),
-	)
+// repeatBets — подпись кнопки повтора и аргументы команды для тех же ставок
+// и правил игры g: "100+50, 21+3: 10, PP: 5" и "100,50,side=10,pairs=5"
+func repeatBets(pr *i18n.Printer, g *game.State) (string, string) {
+	parts := make([]string, len(g.InitialBets))
+	for i, b := range g.InitialBets {
+		parts[i] = strconv.Itoa(b)
+	}
+
+	label, args := strings.Join(parts, "+"), strings.Join(parts, ",")
+	if g.Variant != game.VariantClassic {
+		label = pr.T("variant_"+g.Variant.Name()) + " " + label
+		args = g.Variant.Name() + "," + args
+	}
+	if g.SideBet > 0 {
+		label += pr.T("btn_side", g.SideBet)
+		args += ",side=" + strconv.Itoa(g.SideBet)
+	}
+	if g.PairsBet > 0 {
+		label += pr.T("btn_pairs", g.PairsBet)
+		args += ",pairs=" + strconv.Itoa(g.PairsBet)
+	}
+	return label, args
+}
+
+// EndGameKeyboard предлагает повторить раунд с теми же ставками по боксам
+func EndGameKeyboard(pr *i18n.Printer, g *game.State) transport.Keyboard {
+	label, args := repeatBets(pr, g)
+
+	return transport.Keyboard{{
+		{Text: pr.T("btn_again", label), Data: CallbackPlayAgain + ":" + args},
+		{Text: pr.T("btn_balance"), Data: CallbackBalance},
+	}, {
+		{Text: pr.T("btn_chips"), Data: CallbackSlip},
+	}}
+}
+
+// BetSlipKeyboard — фишки, быстрые суммы, любимые ставки и раздача для ставки amount.
+// Каждая кнопка несет сумму, которая получится после нажатия; «макс.» — лимит или баланс.
+func BetSlipKeyboard(pr *i18n.Printer, p *player.Player, maxBet, amount int) transport.Keyboard {
+	slip := func(text string, bet int) transport.Button {
+		return transport.Button{Text: text, Data: fmt.Sprintf("%s:%d", CallbackSlip, bet)}
+	}
+
+	var chipRow []transport.Button
+	for _, c := range chips {
+		if c <= maxBet {
+			chipRow = append(chipRow, slip(pr.T("btn_chip", c), amount+c))
+		}
+	}
+
+	kb := transport.Keyboard{chipRow, {
+		slip(pr.T("btn_slip_clear"), 0),
+		slip(pr.T("btn_slip_half"), amount/2),
+		slip(pr.T("btn_slip_max"), min(maxBet, p.Balance)),
+	}, {
+		slip(pr.T("btn_slip_repeat", p.LastBet), p.LastBet),
+		slip(pr.T("btn_slip_double", 2*p.LastBet), 2*p.LastBet),
+	}}
+
+	if len(p.FavoriteBets) > 0 {
+		row := make([]transport.Button, 0, len(p.FavoriteBets))
+		for _, bet := range p.FavoriteBets {
+			row = append(row, slip(pr.T("btn_slip_favorite", bet), bet))
+		}
+		kb = append(kb, row)
+	}
+
+	if amount > 0 {
+		save := pr.T("btn_slip_save")
+		if slices.Contains(p.FavoriteBets, amount) {
+			save = pr.T("btn_slip_unsave")
+		}
+		kb = append(kb, []transport.Button{
+			{Text: pr.T("btn_slip_deal", amount), Data: fmt.Sprintf("%s:%d", CallbackSlipDeal, amount)},
+			{Text: save, Data: fmt.Sprintf("%s:%d", CallbackSlipFav, amount)},
+		})
+	}
+	return kb
+}
+
+// TournamentKeyboard предлагает следующий турнирный раунд с теми же ставками
+func TournamentKeyboard(pr *i18n.Printer, g *game.State) transport.Keyboard {
+	label, args := repeatBets(pr, g)
+
+	return transport.Keyboard{{
+		{Text: pr.T("btn_tournament_next", label), Data: CallbackTournamentPlay + ":" + args},
+	}}
+}
+
+// TableKeyboard — кнопки стола группового чата в фазе ставок
+func TableKeyboard(pr *i18n.Printer, defaultBet int) transport.Keyboard {
+	return transport.Keyboard{{
+		{Text: pr.T("btn_table_bet", defaultBet), Data: CallbackTableBet},
+		{Text: pr.T("btn_table_leave"), Data: CallbackTableLeave},
+		{Text: pr.T("btn_balance"), Data: CallbackBalance},
+	}}
+}
+
+// LanguageKeyboard — по кнопке на язык, каждая подписана на своем языке
+func LanguageKeyboard() transport.Keyboard {
+	row := make([]transport.Button, 0, len(i18n.Languages))
+	for _, lang := range i18n.Languages {
+		row = append(row, transport.Button{
+			Text: i18n.New(lang).T("lang_name"),
+			Data: fmt.Sprintf("%s:%s", CallbackLang, lang),
+		})
+	}
+	return transport.Keyboard{row}
 }
@@ -0,0 +1,143 @@
+package bot
+
+import (
+	"errors"
+	"log"
+
+	"blackjack/internal/fair"
+	"blackjack/internal/game"
+	"blackjack/internal/table"
+	"blackjack/internal/tournament"
+)
+
+// ============== ОСТАНОВКА И ВОССТАНОВЛЕНИЕ ==============
+
+// Shutdown сохраняет незавершенные игры перед остановкой: личные игры
+// доигрываются после запуска (Restore), раунды за столами отменяются
+// с возвратом ставок — места за столом в базе не хранятся
+func (h *Handler) Shutdown() {
+	for chatID, g := range h.games.All() {
+		g.Lock()
+		if g.IsActive {
+			if err := h.rounds.Save(g.RoundID, g.Deck.Drawn(), g.Events); err != nil {
+				log.Printf("Failed to save game in chat %d: %v", chatID, err)
+			}
+			// таймеры хода и поздние нажатия больше не трогают игру
+			g.IsActive = false
+		}
+		g.Unlock()
+	}
+
+	for _, t := range h.tables.All() {
+		t.Lock()
+		h.abortTable(t)
+		t.Unlock()
+	}
+}
+
+// abortTable возвращает все ставки стола; вызывается под блокировкой стола
+func (h *Handler) abortTable(t *table.Table) {
+	tp := tablePrinter(t)
+
+	switch {
+	case t.Phase == table.PhasePlaying:
+		// ставка руки уже включает удвоения и сплиты; бесплатные фишки не возвращаются
+		refunds := make(map[int64]int)
+		for _, hand := range t.Game.Hands {
+			if seat := t.Owner(hand); seat != nil {
+				refunds[seat.UserID] += hand.Real()
+			}
+		}
+		for userID, amount := range refunds {
+			h.refund(userID, amount)
+		}
+
+		if _, err := h.rounds.Finish(t.Game.RoundID, t.Game.Deck.Drawn(), t.Game.Events); err != nil {
+			log.Printf("Failed to close table round: %v", err)
+		}
+		t.Reset()
+
+	case len(t.Bettors()) > 0:
+		h.refundTable(t)
+
+	default:
+		return
+	}
+
+	h.send(t.ChatID, tp.T("table_aborted"))
+}
+
+// Restore возобновляет личные игры, сохраненные при остановке:
+// состояние восстанавливается повтором журнала событий раунда
+func (h *Handler) Restore() {
+	rounds, err := h.rounds.Active()
+	if err != nil {
+		log.Printf("Failed to load active rounds: %v", err)
+		return
+	}
+
+	for _, rnd := range rounds {
+		// групповые раунды отменяются при остановке, раунды API доигрывает
+		// сервер API, а без журнала (бот упал, не успев сохраниться) повторять нечего
+		if rnd.ChatID < 0 || rnd.API || len(rnd.Events) == 0 {
+			continue
+		}
+
+		src := fair.NewSource(rnd.ServerSeed, rnd.ClientSeed, rnd.ID)
+		g, err := game.Replay(src, rnd.Events, nil)
+		if err != nil {
+			log.Printf("Failed to restore round #%d: %v", rnd.ID, err)
+			continue
+		}
+
+		// в личном чате ID чата совпадает с ID пользователя
+		g.RoundID = rnd.ID
+		g.PlayerID = rnd.ChatID
+		g.BlackjackPays = h.cfg.BlackjackPays
+
+		// побочные ставки уже рассчитаны при раздаче; нужны для сводки и повтора
+		g.SideResults = rnd.SideBets
+		for _, r := range rnd.SideBets {
+			if r.Kind == game.SidePairs {
+				g.PairsBet = r.Bet
+			} else {
+				g.SideBet = r.Bet
+			}
+		}
+
+		// турнирный раунд доигрывается на турнирные фишки
+		e, err := h.tournaments.ByRound(rnd.ID)
+		switch {
+		case err == nil:
+			g.TournamentID = e.TournamentID
+		case !errors.Is(err, tournament.ErrNotEntered):
+			log.Printf("Failed to check round #%d for a tournament: %v", rnd.ID, err)
+			continue
+		}
+
+		p, err := h.gamePlayer(g)
+		if err != nil {
+			log.Printf("Failed to load player %d: %v", g.PlayerID, err)
+			continue
+		}
+
+		g.Images = p.CardImages
+
+		g.Lock()
+		h.games.Set(rnd.ChatID, g)
+		h.live.Track(rnd.ChatID, g, nil)
+
+		// журнал кончается ходом, после которого рука могла закрыться
+		if hand := g.Current(); (hand == nil || hand.IsStand) && !g.NextHand() {
+			h.finishGame(rnd.ChatID, g, p)
+		} else {
+			pr := printer(p)
+			h.sendGame(rnd.ChatID, g,
+				pr.T("game_restored", h.formatGameStatus(pr, g, false)),
+				GameKeyboard(pr, h.getKeyboardOptions(g, p)))
+		}
+		g.Unlock()
+
+		log.Printf("Restored round #%d in chat %d", rnd.ID, rnd.ChatID)
+	}
+}
@@ -0,0 +1,700 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackjack/internal/achievement"
+	"blackjack/internal/fair"
+	"blackjack/internal/game"
+	"blackjack/internal/i18n"
+	"blackjack/internal/ledger"
+	"blackjack/internal/round"
+	"blackjack/internal/table"
+	"blackjack/internal/transport"
+)
+
+// ============== ГРУППОВЫЕ СТОЛЫ ==============
+
+// tablePrinter — язык общих сообщений стола
+func tablePrinter(t *table.Table) *i18n.Printer {
+	return i18n.New(i18n.Lang(t.Lang))
+}
+
+// tableNames подписывает руки в трансляции именами владельцев
+func tableNames(t *table.Table) func(*game.Hand) string {
+	return func(hand *game.Hand) string {
+		if seat := t.Owner(hand); seat != nil {
+			return seat.Name
+		}
+		return ""
+	}
+}
+
+// openTable возвращает стол чата; новый стол говорит на языке того, кто его открыл
+func (h *Handler) openTable(chatID int64, pr *i18n.Printer) *table.Table {
+	t := h.tables.GetOrCreate(chatID)
+	t.Lock()
+	if t.Lang == "" {
+		t.Lang = string(pr.Lang())
+	}
+	return t
+}
+
+func (h *Handler) HandleGroupMessage(chatID int64, from transport.User, cmd string, args []string) {
+	pr := h.userPrinter(from)
+
+	switch cmd {
+	case "/play", "/bet":
+		h.handleTableBet(chatID, from, pr, args)
+	case "/join":
+		h.handleTableJoin(chatID, from, pr)
+	case "/leave":
+		h.handleTableLeave(chatID, from, pr)
+	case "/table":
+		if len(args) > 0 && strings.ToLower(args[0]) == "rules" {
+			h.handleTableRules(chatID, pr, args[1:])
+			return
+		}
+		if len(args) > 0 {
+			h.handleTableVariant(chatID, pr, args[0])
+			return
+		}
+		h.handleTableShow(chatID, pr)
+	case "/start", "/help":
+		h.send(chatID, pr.T("table_help", table.MaxSeats))
+	case "/balance":
+		h.HandleBalance(chatID, from.ID)
+	case "/top":
+		h.HandleTop(chatID, pr)
+	case "/seed":
+		h.HandleSeed(chatID, from.ID, args)
+	case "/verify":
+		h.HandleVerify(chatID, pr, args)
+	case "/replay":
+		h.HandleReplay(chatID, pr, args)
+	case "/lang":
+		h.HandleLang(chatID, from.ID, args)
+	case "/token":
+		// токен нельзя показывать всему чату
+		h.send(chatID, pr.T("token_private_only"))
+	case "/bonus":
+		h.HandleBonus(chatID, from.ID, pr)
+	case "/achievements":
+		h.HandleAchievements(chatID, from.ID, pr)
+	case "/tournament":
+		h.HandleTournament(chatID, from, pr, args, true)
+	case "/live":
+		h.HandleLive(chatID, pr)
+	}
+}
+
+func (h *Handler) handleTableJoin(chatID int64, from transport.User, pr *i18n.Printer) {
+	t := h.openTable(chatID, pr)
+	defer t.Unlock()
+
+	if _, err := t.Join(from.ID, from.Name); err != nil {
+		h.send(chatID, tableError(pr, err))
+		return
+	}
+
+	tp := tablePrinter(t)
+	h.sendWithKeyboard(chatID, h.formatTableLobby(tp, t), TableKeyboard(tp, h.cfg.DefaultBet))
+}
+
+func (h *Handler) handleTableLeave(chatID int64, from transport.User, pr *i18n.Printer) {
+	t := h.tables.Get(chatID)
+	if t == nil {
+		h.send(chatID, tableError(pr, table.ErrNotSeated))
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	refund, err := t.Leave(from.ID)
+	if err != nil {
+		h.send(chatID, tableError(pr, err))
+		return
+	}
+
+	if refund > 0 {
+		h.refund(from.ID, refund)
+	}
+
+	tp := tablePrinter(t)
+	h.send(chatID, tp.T("table_left", from.Name, h.formatTableLobby(tp, t)))
+
+	// Оставшиеся уже поставили — раздаем, не дожидаясь таймера
+	if t.AllBet() {
+		h.dealTable(t)
+	}
+}
+
+func (h *Handler) handleTableShow(chatID int64, pr *i18n.Printer) {
+	t := h.tables.Get(chatID)
+	if t == nil {
+		h.sendWithKeyboard(chatID, pr.T("table_empty"), TableKeyboard(pr, h.cfg.DefaultBet))
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.Phase == table.PhasePlaying {
+		h.sendTableGame(t, "")
+		return
+	}
+	tp := tablePrinter(t)
+	h.sendWithKeyboard(chatID, h.formatTableLobby(tp, t), TableKeyboard(tp, h.cfg.DefaultBet))
+}
+
+// handleTableVariant переключает правила стола: /table spanish или /table classic
+func (h *Handler) handleTableVariant(chatID int64, pr *i18n.Printer, name string) {
+	// в Switch один игрок ведет оба бокса, за столом у каждого свой
+	v, ok := game.ParseVariant(strings.ToLower(name))
+	if !ok || v == game.VariantSwitch {
+		h.send(chatID, pr.T("table_variant_usage"))
+		return
+	}
+
+	t := h.openTable(chatID, pr)
+	defer t.Unlock()
+
+	if err := t.SetVariant(v); err != nil {
+		h.send(chatID, tableError(pr, err))
+		return
+	}
+
+	tp := tablePrinter(t)
+	h.sendWithKeyboard(chatID, tp.T("table_variant", tp.T("variant_"+v.Name()))+"\n\n"+h.formatTableLobby(tp, t),
+		TableKeyboard(tp, h.cfg.DefaultBet))
+}
+
+// handleTableRules задает правила дома: /table rules charlie=5 21wins ties или /table rules off
+func (h *Handler) handleTableRules(chatID int64, pr *i18n.Printer, args []string) {
+	if len(args) == 0 {
+		h.send(chatID, pr.T("table_rules_usage", game.MinCharlie, game.MaxCharlie))
+		return
+	}
+	rules, err := game.ParseRules(args)
+	if err != nil {
+		h.send(chatID, pr.T("table_rules_usage", game.MinCharlie, game.MaxCharlie))
+		return
+	}
+
+	t := h.openTable(chatID, pr)
+	defer t.Unlock()
+
+	if err := t.SetRules(rules); err != nil {
+		h.send(chatID, tableError(pr, err))
+		return
+	}
+
+	tp := tablePrinter(t)
+	text := tp.T("table_rules_off")
+	if rules != (game.Rules{}) {
+		text = tp.T("table_rules", formatRules(tp, rules))
+	}
+	h.sendWithKeyboard(chatID, text+"\n\n"+h.formatTableLobby(tp, t), TableKeyboard(tp, h.cfg.DefaultBet))
+}
+
+func (h *Handler) handleTableBet(chatID int64, from transport.User, pr *i18n.Printer, args []string) {
+	bet := h.cfg.DefaultBet
+	if len(args) > 0 {
+		b, err := strconv.Atoi(args[0])
+		if err != nil || b <= 0 {
+			h.send(chatID, pr.T("invalid_bet", "/bet", h.cfg.DefaultBet))
+			return
+		}
+		bet = b
+	}
+
+	if bet < h.cfg.MinBet || bet > h.cfg.MaxBet {
+		h.send(chatID, pr.T("bet_range", h.cfg.MinBet, h.cfg.MaxBet))
+		return
+	}
+
+	t := h.openTable(chatID, pr)
+	defer t.Unlock()
+
+	if t.Phase != table.PhaseBetting {
+		h.send(chatID, tableError(pr, table.ErrWrongPhase))
+		return
+	}
+
+	if t.Seat(from.ID) == nil {
+		if _, err := t.Join(from.ID, from.Name); err != nil {
+			h.send(chatID, tableError(pr, err))
+			return
+		}
+	}
+
+	p, err := h.getPlayer(from.ID)
+	if err != nil {
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	if !p.CanAfford(bet) && t.Seat(from.ID).Bet == 0 {
+		if ok, wait := h.tryBailout(p); ok {
+			h.send(chatID, pr.T("table_bailout", from.Name, p.Balance))
+		} else if wait > 0 {
+			h.send(chatID, pr.T("table_no_funds_bailout", from.Name, p.Balance, wait))
+			return
+		}
+	}
+
+	if !p.CanAfford(bet) {
+		h.send(chatID, pr.T("table_no_funds", from.Name, p.Balance))
+		return
+	}
+
+	if err := t.PlaceBet(from.ID, bet); err != nil {
+		h.send(chatID, tableError(pr, err))
+		return
+	}
+
+	// место уже занято ставкой; без фишек в базе снимаем ее обратно
+	if err := h.changeBalance(p, ledger.Change{Kind: ledger.KindBet, Amount: -bet}); err != nil {
+		if !errors.Is(err, ledger.ErrNegative) {
+			log.Printf("Failed to charge table bet: %v", err)
+		}
+		t.Seat(from.ID).Bet = 0
+		h.send(chatID, pr.T("table_no_funds", from.Name, p.Balance))
+		return
+	}
+	p.LastBet = bet
+	h.savePlayer(p)
+
+	if t.AllBet() {
+		h.dealTable(t)
+		return
+	}
+
+	// Первая ставка запускает отсчет до раздачи
+	if t.Deadline.IsZero() {
+		deadline := time.Now().Add(h.cfg.TableBetTime)
+		t.Deadline = deadline
+		time.AfterFunc(h.cfg.TableBetTime, func() {
+			h.onTableDeadline(chatID, deadline)
+		})
+	}
+
+	tp := tablePrinter(t)
+	h.sendWithKeyboard(chatID, h.formatTableLobby(tp, t), TableKeyboard(tp, h.cfg.DefaultBet))
+}
+
+func (h *Handler) onTableDeadline(chatID int64, deadline time.Time) {
+	t := h.tables.Get(chatID)
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	// Таймер мог устареть: раздача уже прошла раньше срока
+	if t.Phase != table.PhaseBetting || !t.Deadline.Equal(deadline) {
+		return
+	}
+
+	// Все со ставками ушли — отсчет начнется со следующей ставки
+	if len(t.Bettors()) == 0 {
+		t.Deadline = time.Time{}
+		return
+	}
+	h.dealTable(t)
+}
+
+// dealTable раздает карты всем местам со ставкой; вызывается под блокировкой стола
+func (h *Handler) dealTable(t *table.Table) {
+	tp := tablePrinter(t)
+	bettors := t.Bettors()
+
+	seeds := make([]string, 0, len(bettors))
+	total := 0
+	for _, s := range bettors {
+		p, err := h.getPlayer(s.UserID)
+		if err != nil {
+			h.send(t.ChatID, tp.T("error"))
+			return
+		}
+		seeds = append(seeds, p.Seed())
+		total += s.Bet
+	}
+
+	rnd, err := round.Begin(h.rounds, t.ChatID, false, strings.Join(seeds, "|"), total)
+	if err != nil {
+		log.Printf("Failed to start table round: %v", err)
+		h.refundTable(t)
+		h.send(t.ChatID, tp.T("table_refunded"))
+		return
+	}
+
+	g := t.Deal(fair.NewSource(rnd.ServerSeed, rnd.ClientSeed, rnd.ID))
+	g.RoundID = rnd.ID
+	g.BlackjackPays = h.cfg.BlackjackPays
+	h.live.Track(t.ChatID, g, tableNames(t))
+
+	if game.IsBlackjack(g.DealerCards) || g.AllHandsComplete() {
+		h.settleTable(t)
+		return
+	}
+
+	h.sendTableGame(t, tp.T("table_round", rnd.ID, rnd.Commitment))
+}
+
+func (h *Handler) refundTable(t *table.Table) {
+	for _, s := range t.Bettors() {
+		h.refund(s.UserID, s.Bet)
+	}
+	t.Reset()
+}
+
+// refund возвращает игроку ставку отмененного раунда
+func (h *Handler) refund(userID int64, amount int) {
+	if _, err := h.ledger.Apply(userID, ledger.Change{Kind: ledger.KindRefund, Amount: amount}); err != nil {
+		log.Printf("Failed to refund player %d: %v", userID, err)
+	}
+}
+
+func (h *Handler) handleTableCallback(callback *transport.Action) {
+	chatID := callback.ChatID
+	pr := h.userPrinter(callback.From)
+
+	switch callback.Data {
+	case CallbackTableBet:
+		h.answerCallback(callback.ID, "")
+		h.handleTableBet(chatID, callback.From, pr, nil)
+		return
+	case CallbackTableLeave:
+		h.answerCallback(callback.ID, "")
+		h.handleTableLeave(chatID, callback.From, pr)
+		return
+	case CallbackBalance:
+		if p, err := h.getPlayer(callback.From.ID); err == nil {
+			h.answerCallback(callback.ID, pr.T("balance_toast", p.Balance))
+		}
+		return
+	}
+
+	t := h.tables.Get(chatID)
+	if t == nil {
+		h.answerCallback(callback.ID, pr.T("game_inactive"))
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.Phase != table.PhasePlaying {
+		h.answerCallback(callback.ID, pr.T("game_inactive"))
+		return
+	}
+
+	seat := t.CurrentSeat()
+	if seat == nil || seat.UserID != callback.From.ID {
+		name := ""
+		if seat != nil {
+			name = seat.Name
+		}
+		h.answerCallback(callback.ID, pr.T("table_turn_of", name))
+		return
+	}
+
+	h.answerCallback(callback.ID, "")
+	h.tableAction(t, seat, callback.Data)
+}
+
+// tableAction выполняет ход текущего игрока; вызывается под блокировкой стола
+func (h *Handler) tableAction(t *table.Table, seat *table.Seat, action string) {
+	tp := tablePrinter(t)
+	g := t.Game
+	hand := g.Current()
+
+	p, err := h.getPlayer(seat.UserID)
+	if err != nil {
+		h.send(t.ChatID, tp.T("error"))
+		return
+	}
+
+	note := ""
+	switch action {
+	case CallbackHit:
+		g.Hit()
+		if hand.IsBust {
+			note = tp.T("table_bust", seat.Name)
+		}
+
+	case CallbackStand:
+		g.Stand()
+		note = tp.T("table_stand", seat.Name)
+
+	case CallbackDouble:
+		if !hand.CanDouble() {
+			return
+		}
+		cost := g.DoubleCost()
+		if !p.CanAfford(cost) {
+			h.send(t.ChatID, tp.T("table_double_no_funds", seat.Name))
+			return
+		}
+		if err := h.changeBalance(p, ledger.Change{Kind: ledger.KindBet, Amount: -cost}); err != nil {
+			if !errors.Is(err, ledger.ErrNegative) {
+				log.Printf("Failed to charge double: %v", err)
+			}
+			h.send(t.ChatID, tp.T("table_double_no_funds", seat.Name))
+			return
+		}
+		g.Double()
+		note = tp.T("table_double", seat.Name)
+
+	case CallbackSplit:
+		if !g.CanSplit() {
+			return
+		}
+		cost := g.SplitCost()
+		if !p.CanAfford(cost) {
+			h.send(t.ChatID, tp.T("table_split_no_funds", seat.Name))
+			return
+		}
+		if err := h.changeBalance(p, ledger.Change{Kind: ledger.KindBet, Amount: -cost}); err != nil {
+			if !errors.Is(err, ledger.ErrNegative) {
+				log.Printf("Failed to charge split: %v", err)
+			}
+			h.send(t.ChatID, tp.T("table_split_no_funds", seat.Name))
+			return
+		}
+		g.Split()
+		note = tp.T("table_split", seat.Name)
+
+	case CallbackSurrender:
+		if !g.CanSurrender() {
+			return
+		}
+		g.Surrender()
+		note = tp.T("table_surrender", seat.Name)
+
+	default:
+		return
+	}
+
+	// Рука доиграна — ход переходит дальше
+	if hand.IsStand && !g.NextHand() {
+		h.settleTable(t)
+		return
+	}
+
+	h.sendTableGame(t, note)
+}
+
+func (h *Handler) sendTableGame(t *table.Table, note string) {
+	tp := tablePrinter(t)
+
+	var sb strings.Builder
+	if note != "" {
+		sb.WriteString(note)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(formatTableGame(tp, t))
+
+	seat := t.CurrentSeat()
+	if seat == nil {
+		h.send(t.ChatID, sb.String())
+		return
+	}
+
+	sb.WriteString(tp.T("table_now", seat.Name))
+
+	opts := GameKeyboardOptions{}
+	if p, err := h.getPlayer(seat.UserID); err == nil {
+		opts = h.getKeyboardOptions(t.Game, p)
+	}
+	t.MessageID = h.sendWithKeyboard(t.ChatID, sb.String(), GameKeyboard(tp, opts))
+	t.TurnStarted = time.Now()
+	h.armTableTimer(t)
+}
+
+// settleTable доигрывает дилера и рассчитывает каждое место; вызывается под блокировкой стола
+func (h *Handler) settleTable(t *table.Table) {
+	tp := tablePrinter(t)
+	g := t.Game
+	g.Finish()
+	h.live.Settle(t.ChatID, g, tableNames(t))
+
+	var sb strings.Builder
+	sb.WriteString(tp.T("table_round_over"))
+
+	var announcements []string
+	for box, seat := range t.Boxes() {
+		p, err := h.getPlayer(seat.UserID)
+		if err != nil {
+			log.Printf("Failed to load player %d: %v", seat.UserID, err)
+			continue
+		}
+
+		totalWin, wins, losses := 0, 0, 0
+		var hands []int
+		for i, hand := range g.Hands {
+			if hand.Box != box {
+				continue
+			}
+			hands = append(hands, i)
+
+			result, winAmount := g.HandResult(hand)
+			text := ""
+			switch result {
+			case game.ResultBlackjack:
+				text = tp.T("result_blackjack", g.BlackjackPayout())
+				wins++
+			case game.ResultPlayerWin:
+				text = tp.T("result_win") + resultReason(tp, g, hand)
+				wins++
+			case game.ResultBonus:
+				text = tp.T("result_bonus", winAmount)
+				wins++
+			case game.ResultDealerWin:
+				text = tp.T("result_loss") + resultReason(tp, g, hand)
+				losses++
+			case game.ResultPush:
+				text = tp.T("result_push")
+			case game.ResultSurrender:
+				text = tp.T("result_surrender", winAmount)
+				losses++
+			}
+			totalWin += winAmount
+
+			sb.WriteString(fmt.Sprintf("%s — %s\n", formatTableHand(tp, t, i), text))
+		}
+
+		p.Settle(wins, losses)
+		if err := h.changeBalance(p, ledger.Change{Kind: ledger.KindWin, Amount: totalWin}); err != nil {
+			log.Printf("Failed to pay player %d: %v", seat.UserID, err)
+		}
+		h.savePlayer(p)
+
+		for _, id := range h.unlockAchievements(seat.UserID, achievement.Round{Game: g, Hands: hands, WinStreak: p.WinStreak}) {
+			announcements = append(announcements, tp.T("table_achievement", seat.Name, tp.T("ach_"+string(id))))
+		}
+
+		sb.WriteString(tp.T("table_player_balance", seat.Name, p.Balance))
+		if totalWin > 0 {
+			sb.WriteString(fmt.Sprintf(" (+%d)", totalWin))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(tp.T("dealer", g.DealerCards, g.DealerScore()))
+	if game.IsBlackjack(g.DealerCards) {
+		sb.WriteString(" — BLACKJACK!")
+	}
+	sb.WriteString(h.revealRound(t.ChatID, tp, g))
+	for _, a := range announcements {
+		sb.WriteString("\n")
+		sb.WriteString(a)
+	}
+
+	t.Reset()
+	h.sendWithKeyboard(t.ChatID, sb.String(), TableKeyboard(tp, h.cfg.DefaultBet))
+}
+
+// ============== ФОРМАТИРОВАНИЕ СТОЛА ==============
+
+func (h *Handler) formatTableLobby(pr *i18n.Printer, t *table.Table) string {
+	var sb strings.Builder
+	sb.WriteString(pr.T("table_lobby", len(t.Seats), table.MaxSeats))
+	if t.Variant != game.VariantClassic {
+		sb.WriteString(pr.T("table_lobby_variant", pr.T("variant_"+t.Variant.Name())))
+	}
+	if t.Rules != (game.Rules{}) {
+		sb.WriteString(pr.T("table_lobby_rules", formatRules(pr, t.Rules)))
+	}
+
+	for i, s := range t.Seats {
+		if s.Bet > 0 {
+			sb.WriteString(pr.T("table_seat_bet", i+1, s.Name, s.Bet))
+		} else {
+			sb.WriteString(pr.T("table_seat_wait", i+1, s.Name))
+		}
+	}
+
+	if !t.Deadline.IsZero() {
+		left := time.Until(t.Deadline).Round(time.Second)
+		if left < 0 {
+			left = 0
+		}
+		sb.WriteString(pr.T("table_countdown", left))
+	} else {
+		sb.WriteString(pr.T("table_bet_hint"))
+	}
+
+	return sb.String()
+}
+
+func formatTableHand(pr *i18n.Printer, t *table.Table, index int) string {
+	g := t.Game
+	hand := g.Hands[index]
+
+	name := "?"
+	if seat := t.Owner(hand); seat != nil {
+		name = seat.Name
+	}
+	if g.BoxHands(hand.Box) > 1 {
+		name = pr.T("table_hand_n", name, handInBox(g, index)+1)
+	}
+
+	status := ""
+	if hand.IsBust {
+		status = " 💥"
+	} else if hand.IsBlackjack() {
+		status = " 🎰"
+	} else if hand.IsStand {
+		status = " ✋"
+	}
+
+	bet := fmt.Sprintf(" · %d", hand.Bet)
+	if hand.Free > 0 {
+		bet = pr.T("hand_bet_free", hand.Real(), hand.Free)
+	}
+	return fmt.Sprintf("🎴 %s: %v (%d)%s%s", name, hand.Cards, hand.Score(), bet, status)
+}
+
+func formatTableGame(pr *i18n.Printer, t *table.Table) string {
+	g := t.Game
+
+	var sb strings.Builder
+	for i := range g.Hands {
+		if i == g.CurrentHand && !g.AllHandsComplete() {
+			sb.WriteString("👉 ")
+		}
+		sb.WriteString(formatTableHand(pr, t, i))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(pr.T("dealer_hidden", g.DealerCards[0]))
+
+	return sb.String()
+}
+
+func tableError(pr *i18n.Printer, err error) string {
+	switch {
+	case errors.Is(err, table.ErrTableFull):
+		return pr.T("table_full", table.MaxSeats)
+	case errors.Is(err, table.ErrAlreadySeated):
+		return pr.T("table_already_seated")
+	case errors.Is(err, table.ErrNotSeated):
+		return pr.T("table_not_seated")
+	case errors.Is(err, table.ErrWrongPhase):
+		return pr.T("table_wrong_phase")
+	case errors.Is(err, table.ErrAlreadyBet):
+		return pr.T("table_already_bet")
+	case errors.Is(err, table.ErrBetsPlaced):
+		return pr.T("table_bets_placed")
+	}
+	return pr.T("error")
+}
@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"blackjack/internal/config"
+	"blackjack/internal/game"
+	"blackjack/internal/table"
+)
+
+// ============== ТАЙМАУТЫ ХОДОВ ==============
+
+// applyTimeout закрывает текущую руку действием из конфига
+func (h *Handler) applyTimeout(g *game.State) {
+	switch h.cfg.TimeoutAction {
+	case config.TimeoutSurrender:
+		g.Surrender()
+	case config.TimeoutForfeit:
+		g.Forfeit()
+	default:
+		g.Stand()
+	}
+}
+
+func (h *Handler) armTurnTimer(chatID int64, g *game.State) {
+	if h.cfg.ActionTimeout <= 0 {
+		return
+	}
+
+	stamp := g.LastAction
+	time.AfterFunc(h.cfg.ActionTimeout, func() {
+		g.Lock()
+		defer g.Unlock()
+
+		// за это время игрок походил или игра закончилась
+		if !g.IsActive || !g.LastAction.Equal(stamp) || h.games.Get(chatID) != g {
+			return
+		}
+		h.expireGame(chatID, g)
+	})
+}
+
+// expireGame закрывает все оставшиеся руки и рассчитывает раунд,
+// заменяя последнее сообщение с кнопками итогом; вызывается под блокировкой игры
+func (h *Handler) expireGame(chatID int64, g *game.State) {
+	p, err := h.gamePlayer(g)
+	if err != nil {
+		log.Printf("Failed to load player %d: %v", g.PlayerID, err)
+		return
+	}
+
+	for g.Current() != nil {
+		h.applyTimeout(g)
+		if !g.NextHand() {
+			break
+		}
+	}
+
+	pr := printer(p)
+	text := pr.T("timeout_expired") + h.settleGame(chatID, g, p)
+	kb := h.endGameKeyboard(pr, g)
+
+	if g.MessageID != 0 {
+		h.editRound(chatID, g.MessageID, g, text, kb)
+		return
+	}
+	h.sendRound(chatID, g, text, kb)
+}
+
+func (h *Handler) armTableTimer(t *table.Table) {
+	if h.cfg.ActionTimeout <= 0 {
+		return
+	}
+
+	stamp := t.TurnStarted
+	time.AfterFunc(h.cfg.ActionTimeout, func() {
+		t.Lock()
+		defer t.Unlock()
+
+		if t.Phase != table.PhasePlaying || !t.TurnStarted.Equal(stamp) {
+			return
+		}
+		h.expireTableTurn(t)
+	})
+}
+
+// expireTableTurn закрывает руку игрока, который не успел походить,
+// и передает ход дальше; вызывается под блокировкой стола
+func (h *Handler) expireTableTurn(t *table.Table) {
+	seat := t.CurrentSeat()
+	if seat == nil {
+		return
+	}
+
+	tp := tablePrinter(t)
+	if t.MessageID != 0 {
+		h.edit(t.ChatID, t.MessageID, formatTableGame(tp, t), nil)
+	}
+
+	h.applyTimeout(t.Game)
+	if !t.Game.NextHand() {
+		h.settleTable(t)
+		return
+	}
+	h.sendTableGame(t, tp.T("table_timeout", seat.Name))
+}
+
+// RunSweeper периодически рассчитывает брошенные игры, которые пропустили
+// таймер хода, убирает пустые столы и запускает и завершает турниры
+func (h *Handler) RunSweeper(ctx context.Context) {
+	ticker := time.NewTicker(h.cfg.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.sweep()
+		}
+	}
+}
+
+func (h *Handler) sweep() {
+	for chatID, g := range h.games.Idle(h.cfg.AbandonedAfter) {
+		g.Lock()
+		if g.IsActive {
+			log.Printf("Settling abandoned game in chat %d", chatID)
+			h.expireGame(chatID, g)
+		} else {
+			h.games.Delete(chatID)
+		}
+		g.Unlock()
+	}
+
+	for _, t := range h.tables.All() {
+		t.Lock()
+		switch {
+		case t.Phase == table.PhasePlaying && time.Since(t.TurnStarted) > h.cfg.AbandonedAfter:
+			log.Printf("Expiring abandoned turn at table %d", t.ChatID)
+			h.expireTableTurn(t)
+		case t.Phase == table.PhaseBetting && len(t.Seats) == 0:
+			h.tables.Delete(t.ChatID)
+		}
+		t.Unlock()
+	}
+
+	h.tickTournaments()
+}
@@ -0,0 +1,547 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackjack/internal/game"
+	"blackjack/internal/i18n"
+	"blackjack/internal/player"
+	"blackjack/internal/tournament"
+	"blackjack/internal/transport"
+)
+
+// формат времени турниров; время всегда в UTC
+const tournamentTimeLayout = "2006-01-02 15:04"
+
+// ============== ТУРНИРЫ ==============
+
+// tournamentWallet — игрок, у которого вместо основного баланса турнирные фишки.
+// Его баланс сохраняется только в турнир, основной кошелек он не трогает.
+func tournamentWallet(p *player.Player, e *tournament.Entry) *player.Player {
+	return &player.Player{
+		UserID:     p.UserID,
+		Balance:    e.Balance,
+		LastBet:    p.LastBet,
+		ClientSeed: p.ClientSeed,
+		Language:   p.Language,
+		CardImages: p.CardImages,
+	}
+}
+
+// gamePlayer — кошелек, из которого идет игра g
+func (h *Handler) gamePlayer(g *game.State) (*player.Player, error) {
+	p, err := h.getPlayer(g.PlayerID)
+	if err != nil || g.TournamentID == 0 {
+		return p, err
+	}
+
+	e, err := h.tournaments.Entry(g.TournamentID, g.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	return tournamentWallet(p, e), nil
+}
+
+// saveGamePlayer сохраняет баланс посреди игры туда, откуда он взят
+func (h *Handler) saveGamePlayer(g *game.State, p *player.Player) {
+	if g.TournamentID == 0 {
+		h.savePlayer(p)
+		return
+	}
+
+	if err := h.tournaments.SetBalance(g.TournamentID, p.UserID, p.Balance); err != nil {
+		log.Printf("Failed to save tournament balance of %d: %v", p.UserID, err)
+	}
+}
+
+// canPlayTournament — турнир идет и у участника остались раунды и фишки
+func (h *Handler) canPlayTournament(id, userID int64) bool {
+	t, err := h.tournaments.Get(id)
+	if err != nil || t.Status != tournament.StatusRunning || !time.Now().Before(t.EndsAt) {
+		return false
+	}
+
+	e, err := h.tournaments.Entry(id, userID)
+	return err == nil && !e.Done(t, h.cfg.MinBet)
+}
+
+// HandleTournament — /tournament: текущий турнир, регистрация и турнирные раунды
+func (h *Handler) HandleTournament(chatID int64, from transport.User, pr *i18n.Printer, args []string, group bool) {
+	sub := ""
+	if len(args) > 0 {
+		sub = strings.ToLower(args[0])
+	}
+
+	switch sub {
+	case "":
+		h.showTournament(chatID, from.ID, pr)
+	case "join":
+		h.joinTournament(chatID, from, pr)
+	case "play":
+		if group {
+			h.send(chatID, pr.T("tournament_private_only"))
+			return
+		}
+		h.handleTournamentPlay(chatID, from.ID, pr, args[1:])
+	default:
+		h.send(chatID, pr.T("tournament_usage"))
+	}
+}
+
+// currentTournament возвращает запланированный или идущий турнир; если его нет,
+// сам отвечает игроку
+func (h *Handler) currentTournament(chatID int64, pr *i18n.Printer) *tournament.Tournament {
+	t, err := h.tournaments.Current()
+	if errors.Is(err, tournament.ErrNotFound) {
+		h.send(chatID, pr.T("tournament_none"))
+		return nil
+	}
+	if err != nil {
+		log.Printf("Failed to get tournament: %v", err)
+		h.send(chatID, pr.T("error"))
+		return nil
+	}
+	return t
+}
+
+func (h *Handler) showTournament(chatID, userID int64, pr *i18n.Printer) {
+	t := h.currentTournament(chatID, pr)
+	if t == nil {
+		return
+	}
+
+	entries, err := h.tournaments.Standings(t.ID)
+	if err != nil {
+		log.Printf("Failed to get standings of tournament #%d: %v", t.ID, err)
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	var entry *tournament.Entry
+	for i := range entries {
+		if entries[i].UserID == userID {
+			entry = &entries[i]
+		}
+	}
+
+	if t.Open() {
+		hint := pr.T("tournament_join_hint")
+		if entry != nil {
+			hint = pr.T("tournament_registered")
+		}
+		h.send(chatID, pr.T("tournament_scheduled",
+			t.ID, formatTournamentTime(t.StartsAt), max(time.Until(t.StartsAt), 0).Round(time.Minute),
+			pr.N("rounds", t.Rounds), t.Chips, formatPrizes(t.Prizes), pr.N("players", len(entries)), hint))
+		return
+	}
+
+	text := formatStandings(pr, t, entries, userID)
+	if entry != nil && !entry.Done(t, h.cfg.MinBet) {
+		text += pr.T("tournament_play_hint", t.Rounds-entry.Played)
+	}
+	h.send(chatID, text)
+}
+
+func (h *Handler) joinTournament(chatID int64, from transport.User, pr *i18n.Printer) {
+	t := h.currentTournament(chatID, pr)
+	if t == nil {
+		return
+	}
+
+	name := from.Name
+	if name == "" {
+		name = strconv.FormatInt(from.ID, 10)
+	}
+
+	if _, err := h.tournaments.Register(t.ID, from.ID, name, t.Chips); err != nil {
+		h.send(chatID, tournamentError(pr, err))
+		return
+	}
+
+	log.Printf("Player %d registered for tournament #%d", from.ID, t.ID)
+	h.send(chatID, pr.T("tournament_joined", from.Name, t.ID, formatTournamentTime(t.StartsAt), t.Chips))
+}
+
+// handleTournamentPlay начинает турнирный раунд: ставки списываются
+// с турнирных фишек, основной баланс не меняется
+func (h *Handler) handleTournamentPlay(chatID, userID int64, pr *i18n.Printer, args []string) {
+	t, err := h.tournaments.Current()
+	if err != nil && !errors.Is(err, tournament.ErrNotFound) {
+		log.Printf("Failed to get tournament: %v", err)
+		h.send(chatID, pr.T("error"))
+		return
+	}
+	if err != nil || t.Status != tournament.StatusRunning || !time.Now().Before(t.EndsAt) {
+		h.send(chatID, pr.T("tournament_not_running"))
+		return
+	}
+
+	if h.games.Get(chatID) != nil {
+		h.send(chatID, pr.T("tournament_round_active"))
+		return
+	}
+
+	bets, o, total, ok := h.parseBets(chatID, pr, "/tournament play", args)
+	if !ok {
+		return
+	}
+
+	// проверяем заранее, чтобы не тратить коммитмент раунда впустую
+	e, err := h.tournaments.Entry(t.ID, userID)
+	switch {
+	case err != nil:
+	case e.RoundID != 0:
+		err = tournament.ErrRoundActive
+	case e.Played >= t.Rounds:
+		err = tournament.ErrNoRounds
+	case e.Balance < total:
+		err = tournament.ErrNoChips
+	}
+	if err != nil {
+		h.send(chatID, tournamentError(pr, err))
+		return
+	}
+
+	p, err := h.getPlayer(userID)
+	if err != nil {
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	rnd, err := h.startRound(chatID, p, total)
+	if err != nil {
+		log.Printf("Failed to start round: %v", err)
+		h.send(chatID, pr.T("error"))
+		return
+	}
+
+	e, err = h.tournaments.StartRound(t.ID, userID, rnd.ID, t.Rounds, total)
+	if err != nil {
+		log.Printf("Failed to start tournament round #%d for %d: %v", rnd.ID, userID, err)
+		h.send(chatID, tournamentError(pr, err))
+		return
+	}
+
+	h.dealGame(chatID, tournamentWallet(p, e), rnd, bets, o, t.ID,
+		pr.T("tournament_round", t.ID, e.Played+1, t.Rounds))
+}
+
+// finishTournamentRound засчитывает рассчитанный раунд, обновляет таблицы
+// участников и возвращает строку с местом игрока
+func (h *Handler) finishTournamentRound(g *game.State, p *player.Player) string {
+	h.tournamentMu.Lock()
+	defer h.tournamentMu.Unlock()
+
+	e, err := h.tournaments.FinishRound(g.TournamentID, p.UserID, p.Balance)
+	if err != nil {
+		log.Printf("Failed to finish tournament round of %d: %v", p.UserID, err)
+		return ""
+	}
+
+	t, err := h.tournaments.Get(g.TournamentID)
+	if err != nil {
+		log.Printf("Failed to get tournament #%d: %v", g.TournamentID, err)
+		return ""
+	}
+
+	entries, err := h.tournaments.Standings(t.ID)
+	if err != nil {
+		log.Printf("Failed to get standings of tournament #%d: %v", t.ID, err)
+		return ""
+	}
+
+	place := 0
+	for i, other := range entries {
+		if other.UserID == p.UserID {
+			place = i + 1
+		}
+	}
+
+	h.updateStandings(t, entries)
+	h.checkTournament(t, entries)
+
+	return printer(p).T("tournament_round_done", e.Balance, e.Played, t.Rounds, place, len(entries))
+}
+
+// updateStandings заменяет таблицу в личном чате каждого участника
+func (h *Handler) updateStandings(t *tournament.Tournament, entries []tournament.Entry) {
+	for _, e := range entries {
+		if e.MessageID == 0 {
+			continue
+		}
+		pr := h.playerPrinter(e.UserID)
+		h.edit(e.UserID, e.MessageID, formatStandings(pr, t, entries, e.UserID), nil)
+	}
+}
+
+// playerPrinter — язык игрока, которому пишем не в ответ на его сообщение
+func (h *Handler) playerPrinter(userID int64) *i18n.Printer {
+	p, err := h.players.Get(userID)
+	if err != nil {
+		return printer(nil)
+	}
+	return printer(p)
+}
+
+// checkTournament завершает идущий турнир, когда все участники доиграли
+// или вышло время, но не посреди чьего-то раунда; вызывается под tournamentMu
+func (h *Handler) checkTournament(t *tournament.Tournament, entries []tournament.Entry) {
+	if t.Status != tournament.StatusRunning {
+		return
+	}
+
+	expired := !time.Now().Before(t.EndsAt)
+	done := true
+	for _, e := range entries {
+		if e.RoundID == 0 {
+			if !e.Done(t, h.cfg.MinBet) {
+				done = false
+			}
+			continue
+		}
+
+		// после конца турнира раунд без игры в памяти (бот упал, не успев его
+		// сохранить, или его не удалось восстановить) уже никто не доиграет
+		if !expired || h.playingRound(e) || !h.forfeitRound(t, e) {
+			return
+		}
+	}
+	if !done && !expired {
+		return
+	}
+
+	h.finishTournament(t)
+}
+
+// playingRound — турнирный раунд участника идет в его личном чате
+func (h *Handler) playingRound(e tournament.Entry) bool {
+	g := h.games.Get(e.UserID)
+	return g != nil && g.RoundID == e.RoundID
+}
+
+// forfeitRound засчитывает недоигранный раунд со сгоревшей ставкой
+// и закрывает его, чтобы Restore не поднял его снова
+func (h *Handler) forfeitRound(t *tournament.Tournament, e tournament.Entry) bool {
+	if err := h.tournaments.ForfeitRound(t.ID, e.UserID, e.RoundID); err != nil {
+		log.Printf("Failed to forfeit round #%d of %d: %v", e.RoundID, e.UserID, err)
+		return false
+	}
+
+	rnd, err := h.rounds.Get(e.RoundID)
+	if err == nil {
+		_, err = h.rounds.Finish(rnd.ID, rnd.Cards, rnd.Events)
+	}
+	if err != nil {
+		log.Printf("Failed to close forfeited round #%d: %v", e.RoundID, err)
+	}
+
+	log.Printf("Forfeited round #%d of %d in tournament #%d", e.RoundID, e.UserID, t.ID)
+	return true
+}
+
+// finishTournament платит призы и рассылает участникам итог
+func (h *Handler) finishTournament(t *tournament.Tournament) {
+	entries, err := h.tournaments.Finish(t.ID)
+	if errors.Is(err, tournament.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to finish tournament #%d: %v", t.ID, err)
+		return
+	}
+	t.Status = tournament.StatusFinished
+
+	log.Printf("Tournament #%d finished, %d players", t.ID, len(entries))
+	h.updateStandings(t, entries)
+
+	for i, e := range entries {
+		pr := h.playerPrinter(e.UserID)
+		text := pr.T("tournament_over", t.ID, i+1, len(entries))
+		if e.Prize > 0 {
+			log.Printf("Tournament #%d: prize %d to player %d", t.ID, e.Prize, e.UserID)
+			text += pr.T("tournament_prize", pr.N("chips", e.Prize))
+		}
+		h.send(e.UserID, text)
+	}
+}
+
+// startTournament открывает игру и присылает участникам таблицу,
+// которая дальше обновляется после каждого раунда
+func (h *Handler) startTournament(t *tournament.Tournament) {
+	started, err := h.tournaments.Start(t.ID)
+	if err != nil {
+		log.Printf("Failed to start tournament #%d: %v", t.ID, err)
+		return
+	}
+	if !started {
+		return
+	}
+	t.Status = tournament.StatusRunning
+
+	entries, err := h.tournaments.Standings(t.ID)
+	if err != nil {
+		log.Printf("Failed to get standings of tournament #%d: %v", t.ID, err)
+		return
+	}
+
+	log.Printf("Tournament #%d started, %d players", t.ID, len(entries))
+	for _, e := range entries {
+		pr := h.playerPrinter(e.UserID)
+		h.send(e.UserID, pr.T("tournament_started", t.ID, pr.N("rounds", t.Rounds), t.Chips, formatTournamentTime(t.EndsAt)))
+
+		id := h.sendWithKeyboard(e.UserID, formatStandings(pr, t, entries, e.UserID), nil)
+		if id == 0 {
+			continue
+		}
+		if err := h.tournaments.SetMessage(t.ID, e.UserID, id); err != nil {
+			log.Printf("Failed to save standings message of %d: %v", e.UserID, err)
+		}
+	}
+
+	h.checkTournament(t, entries)
+}
+
+// tickTournaments запускает турнир, когда подошло время, и завершает
+// просроченный; вызывается из sweep
+func (h *Handler) tickTournaments() {
+	h.tournamentMu.Lock()
+	defer h.tournamentMu.Unlock()
+
+	t, err := h.tournaments.Current()
+	if errors.Is(err, tournament.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get tournament: %v", err)
+		return
+	}
+
+	switch {
+	case t.Open() && !time.Now().Before(t.StartsAt):
+		h.startTournament(t)
+
+	case t.Status == tournament.StatusRunning:
+		entries, err := h.tournaments.Standings(t.ID)
+		if err != nil {
+			log.Printf("Failed to get standings of tournament #%d: %v", t.ID, err)
+			return
+		}
+		h.checkTournament(t, entries)
+	}
+}
+
+// adminTournament планирует турнир: /admin tournament <старт> <раунды> <фишки> <призы>.
+// Старт — через сколько (2h) или когда по UTC (2026-10-20T18:00).
+func (h *Handler) adminTournament(chatID, adminID int64, pr *i18n.Printer, args []string) {
+	if len(args) != 4 {
+		h.send(chatID, pr.T("admin_tournament_invalid"))
+		return
+	}
+
+	startsAt, err := parseTournamentStart(args[0], time.Now())
+	rounds, errRounds := strconv.Atoi(args[1])
+	chips, errChips := strconv.Atoi(args[2])
+	prizes, errPrizes := tournament.ParsePrizes(args[3])
+	if err != nil || errRounds != nil || errChips != nil || errPrizes != nil ||
+		rounds <= 0 || chips < h.cfg.MinBet || len(prizes) == 0 {
+		h.send(chatID, pr.T("admin_tournament_invalid"))
+		return
+	}
+
+	t := &tournament.Tournament{
+		StartsAt:  startsAt,
+		EndsAt:    startsAt.Add(h.cfg.TournamentDuration),
+		Rounds:    rounds,
+		Chips:     chips,
+		Prizes:    prizes,
+		CreatedBy: adminID,
+	}
+	if err := h.tournaments.Create(t); err != nil {
+		h.send(chatID, tournamentError(pr, err))
+		return
+	}
+
+	log.Printf("Admin %d: tournament #%d at %s, %d rounds, %d chips, prizes %v",
+		adminID, t.ID, t.StartsAt.UTC().Format(time.RFC3339), rounds, chips, prizes)
+	h.send(chatID, pr.T("admin_tournament_created",
+		t.ID, formatTournamentTime(t.StartsAt), pr.N("rounds", rounds), t.Chips, formatPrizes(prizes)))
+}
+
+func parseTournamentStart(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(d).Truncate(time.Minute), nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04", s, time.UTC)
+}
+
+func formatTournamentTime(t time.Time) string {
+	return t.UTC().Format(tournamentTimeLayout)
+}
+
+func formatPrizes(prizes []int) string {
+	if len(prizes) == 0 {
+		return "—"
+	}
+
+	parts := make([]string, len(prizes))
+	for i, p := range prizes {
+		parts[i] = fmt.Sprintf("%s %d", placeMark(i), p)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func formatStandings(pr *i18n.Printer, t *tournament.Tournament, entries []tournament.Entry, userID int64) string {
+	var sb strings.Builder
+	sb.WriteString(pr.T("tournament_title", t.ID, pr.N("rounds", t.Rounds)))
+
+	if t.Status == tournament.StatusFinished {
+		sb.WriteString(pr.T("tournament_finished"))
+	} else {
+		sb.WriteString(pr.T("tournament_until", formatTournamentTime(t.EndsAt)))
+	}
+
+	if len(entries) == 0 {
+		sb.WriteString(pr.T("tournament_empty"))
+	}
+	for i, e := range entries {
+		suffix := ""
+		if e.Prize > 0 {
+			suffix += pr.T("tournament_prize_suffix", e.Prize)
+		}
+		if e.UserID == userID {
+			suffix += pr.T("tournament_you")
+		}
+		sb.WriteString(pr.T("tournament_line", placeMark(i), e.Name, e.Balance, e.Played, t.Rounds, suffix))
+	}
+
+	return sb.String()
+}
+
+func tournamentError(pr *i18n.Printer, err error) string {
+	switch {
+	case errors.Is(err, tournament.ErrNotFound):
+		return pr.T("tournament_none")
+	case errors.Is(err, tournament.ErrExists):
+		return pr.T("admin_tournament_exists")
+	case errors.Is(err, tournament.ErrClosed):
+		return pr.T("tournament_closed")
+	case errors.Is(err, tournament.ErrRegistered):
+		return pr.T("tournament_registered")
+	case errors.Is(err, tournament.ErrNotEntered):
+		return pr.T("tournament_not_entered")
+	case errors.Is(err, tournament.ErrNoRounds):
+		return pr.T("tournament_no_rounds")
+	case errors.Is(err, tournament.ErrNoChips):
+		return pr.T("tournament_no_chips")
+	case errors.Is(err, tournament.ErrRoundActive):
+		return pr.T("tournament_round_active")
+	default:
+		log.Printf("Tournament command failed: %v", err)
+		return pr.T("error")
+	}
+}
@@ -2,11 +2,30 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackjack/internal/game"
 
 	"github.com/joho/godotenv"
 )
 
+// Что делать с рукой, если игрок не походил вовремя
+const (
+	TimeoutStand     = "stand"
+	TimeoutSurrender = "surrender"
+	TimeoutForfeit   = "forfeit"
+)
+
+// секрет вебхука по правилам Telegram: 1–256 символов A-Z, a-z, 0-9, _ и -
+var webhookSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 type Config struct {
 	BotToken      string
 	DatabasePath  string
@@ -14,29 +33,275 @@ type Config struct {
 	DefaultBet    int
 	MinBet        int
 	MaxBet        int
+	MaxBoxes      int
 	BlackjackPays float64
+
+	// выплаты побочных ставок 21+3 и Perfect Pairs «к одному»
+	SidePaytable  game.Paytable
+	PairsPaytable game.PairPaytable
+
+	// ежедневный бонус умножается на серию дней подряд, но не больше чем на BonusMaxStreak
+	DailyBonus     int
+	BonusMaxStreak int
+
+	// помощь банкроту: баланс до BailoutAmount не чаще раза в BailoutCooldown
+	BailoutAmount   int
+	BailoutCooldown time.Duration
+
+	// сколько длится турнир после старта; потом новые раунды не начинаются
+	TournamentDuration time.Duration
+
+	// сколько ждать ставок за групповым столом после первой
+	TableBetTime time.Duration
+
+	// время на ход (0 — без ограничения) и что делать по его истечении
+	ActionTimeout time.Duration
+	TimeoutAction string
+
+	// как часто и после какого простоя убирать брошенные игры
+	SweepInterval  time.Duration
+	AbandonedAfter time.Duration
+
+	// сколько ждать текущие обработчики при остановке
+	ShutdownTimeout time.Duration
+
+	// режим вебхука включается публичным адресом; без него — long polling
+	WebhookURL    string
+	WebhookListen string
+	WebhookSecret string
+
+	// сертификат для встроенного HTTPS; пусто — обычный HTTP за прокси
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// адрес HTTP API (cmd/api)
+	APIListen string
+
+	// live-трансляция столов по WebSocket: адрес (пусто — выключена),
+	// публичный адрес для ссылок и секрет для ключей доступа
+	LiveListen string
+	LiveURL    string
+	LiveSecret string
+
+	// пользователи, которым доступна /admin
+	AdminIDs []int64
+}
+
+func (c *Config) IsAdmin(userID int64) bool {
+	return slices.Contains(c.AdminIDs, userID)
+}
+
+func (c *Config) Live() bool {
+	return c.LiveListen != ""
+}
+
+func (c *Config) Webhook() bool {
+	return c.WebhookURL != ""
 }
 
 func Load() (*Config, error) {
-	godotenv.Load()
+	cfg, err := LoadLocal()
+	if err != nil {
+		return nil, err
+	}
 
-	token := os.Getenv("BOT_TOKEN")
-	if token == "" {
+	if cfg.BotToken == "" {
 		return nil, fmt.Errorf("BOT_TOKEN is not set")
 	}
+	return cfg, nil
+}
+
+// LoadLocal читает конфиг без обязательного токена — для локальных фронтендов
+func LoadLocal() (*Config, error) {
+	godotenv.Load()
+
+	token := os.Getenv("BOT_TOKEN")
 
 	dbPath := os.Getenv("DATABASE_PATH")
 	if dbPath == "" {
 		dbPath = "./blackjack.db"
 	}
 
+	actionTimeout, err := durationEnv("ACTION_TIMEOUT", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	timeoutAction := os.Getenv("TIMEOUT_ACTION")
+	switch timeoutAction {
+	case "":
+		timeoutAction = TimeoutStand
+	case TimeoutStand, TimeoutSurrender, TimeoutForfeit:
+	default:
+		return nil, fmt.Errorf("TIMEOUT_ACTION must be %s, %s or %s", TimeoutStand, TimeoutSurrender, TimeoutForfeit)
+	}
+
+	abandonedAfter, err := durationEnv("ABANDONED_AFTER", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	bailoutCooldown, err := durationEnv("BAILOUT_COOLDOWN", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	sidePaytable, err := paytableEnv("SIDE_PAYTABLE", game.PokerHands, game.Paytable{
+		game.PokerFlush:         5,
+		game.PokerStraight:      10,
+		game.PokerThreeOfAKind:  30,
+		game.PokerStraightFlush: 40,
+		game.PokerSuitedTrips:   100,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pairsPaytable, err := paytableEnv("PAIRS_PAYTABLE", game.PairHands, game.PairPaytable{
+		game.PairMixed:    6,
+		game.PairColoured: 12,
+		game.PairPerfect:  25,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tournamentDuration, err := durationEnv("TOURNAMENT_DURATION", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout, err := durationEnv("SHUTDOWN_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookURL := os.Getenv("WEBHOOK_URL")
+	if webhookURL != "" {
+		u, err := url.Parse(webhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("WEBHOOK_URL must be an absolute https URL")
+		}
+	}
+
+	webhookListen := os.Getenv("WEBHOOK_LISTEN")
+	if webhookListen == "" {
+		webhookListen = ":8443"
+	}
+
+	webhookSecret := os.Getenv("WEBHOOK_SECRET")
+	if webhookURL != "" && !webhookSecretRe.MatchString(webhookSecret) {
+		return nil, fmt.Errorf("WEBHOOK_SECRET must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
+
+	apiListen := os.Getenv("API_LISTEN")
+	if apiListen == "" {
+		apiListen = ":8080"
+	}
+
+	liveListen := os.Getenv("LIVE_LISTEN")
+	liveURL := strings.TrimSuffix(os.Getenv("LIVE_URL"), "/")
+	liveSecret := os.Getenv("LIVE_SECRET")
+	if liveListen != "" {
+		if len(liveSecret) < 16 {
+			return nil, fmt.Errorf("LIVE_SECRET must be at least 16 characters")
+		}
+		if liveURL == "" {
+			liveURL = "http://localhost" + liveListen
+			if !strings.HasPrefix(liveListen, ":") {
+				liveURL = "http://" + liveListen
+			}
+		}
+	}
+
+	var adminIDs []int64
+	for _, s := range strings.Split(os.Getenv("ADMIN_IDS"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ADMIN_IDS: %w", err)
+		}
+		adminIDs = append(adminIDs, id)
+	}
+
+	certFile := os.Getenv("TLS_CERT_FILE")
+	keyFile := os.Getenv("TLS_KEY_FILE")
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
 	return &Config{
-		BotToken:      token,
-		DatabasePath:  dbPath,
-		StartBalance:  1000,
-		DefaultBet:    100,
-		MinBet:        10,
-		MaxBet:        10000,
-		BlackjackPays: 2.5,
+		BotToken:           token,
+		DatabasePath:       dbPath,
+		StartBalance:       1000,
+		DefaultBet:         100,
+		MinBet:             10,
+		MaxBet:             10000,
+		MaxBoxes:           3,
+		BlackjackPays:      2.5,
+		SidePaytable:       sidePaytable,
+		PairsPaytable:      pairsPaytable,
+		DailyBonus:         100,
+		BonusMaxStreak:     7,
+		BailoutAmount:      500,
+		BailoutCooldown:    bailoutCooldown,
+		TournamentDuration: tournamentDuration,
+		TableBetTime:       20 * time.Second,
+		ActionTimeout:      actionTimeout,
+		TimeoutAction:      timeoutAction,
+		SweepInterval:      time.Minute,
+		AbandonedAfter:     abandonedAfter,
+		ShutdownTimeout:    shutdownTimeout,
+		WebhookURL:         webhookURL,
+		WebhookListen:      webhookListen,
+		WebhookSecret:      webhookSecret,
+		TLSCertFile:        certFile,
+		TLSKeyFile:         keyFile,
+		APIListen:          apiListen,
+		LiveListen:         liveListen,
+		LiveURL:            liveURL,
+		LiveSecret:         liveSecret,
+		AdminIDs:           adminIDs,
 	}, nil
 }
+
+// paytableEnv читает выплаты вида "flush=5,straight=10" для комбинаций из hands;
+// неуказанные комбинации платят по умолчанию
+func paytableEnv[P ~map[H]int, H ~string](key string, hands []H, def P) (P, error) {
+	pays := maps.Clone(def)
+
+	v := os.Getenv(key)
+	if v == "" {
+		return pays, nil
+	}
+
+	for _, part := range strings.Split(v, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		hand := H(name)
+		if !ok || !slices.Contains(hands, hand) {
+			return nil, fmt.Errorf("invalid %s: unknown hand %q", key, name)
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s: bad payout for %s", key, name)
+		}
+		pays[hand] = n
+	}
+	return pays, nil
+}
+
+func durationEnv(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
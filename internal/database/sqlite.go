@@ -28,8 +28,9 @@ func New(path string) (*DB, error) {
 	return &DB{db}, nil
 }
 
-func migrate(db *sql.DB) error {
-	schema := `
+// migrations применяются по порядку, номер последней хранится в PRAGMA user_version
+var migrations = []string{
+	`
 	CREATE TABLE IF NOT EXISTS players (
 		chat_id INTEGER PRIMARY KEY,
 		balance INTEGER DEFAULT 1000,
@@ -44,8 +45,181 @@ func migrate(db *sql.DB) error {
 
 	CREATE INDEX IF NOT EXISTS idx_players_balance ON players(balance);
 	CREATE INDEX IF NOT EXISTS idx_players_games ON players(games);
+	`,
+	`
+	ALTER TABLE players ADD COLUMN client_seed TEXT NOT NULL DEFAULT '';
+
+	CREATE TABLE rounds (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		chat_id INTEGER NOT NULL,
+		server_seed TEXT NOT NULL,
+		commitment TEXT NOT NULL,
+		client_seed TEXT NOT NULL DEFAULT '',
+		bet INTEGER NOT NULL DEFAULT 0,
+		cards TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		finished_at DATETIME
+	);
+
+	CREATE INDEX idx_rounds_chat_status ON rounds(chat_id, status);
+	`,
+	`
+	ALTER TABLE rounds ADD COLUMN events TEXT NOT NULL DEFAULT '';
+	`,
+	// Игроки теперь по ID пользователя Telegram. Для личных чатов ID чата
+	// совпадает с ID пользователя, поэтому такие строки переносятся как есть.
+	// Групповые кошельки (отрицательный chat_id) никому не принадлежат —
+	// они откладываются в архив.
+	`
+	CREATE TABLE players_group_archive AS
+		SELECT * FROM players WHERE chat_id < 0;
+
+	CREATE TABLE players_new (
+		user_id INTEGER PRIMARY KEY,
+		balance INTEGER DEFAULT 1000,
+		wins INTEGER DEFAULT 0,
+		losses INTEGER DEFAULT 0,
+		draws INTEGER DEFAULT 0,
+		games INTEGER DEFAULT 0,
+		last_bet INTEGER DEFAULT 100,
+		client_seed TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	INSERT INTO players_new (user_id, balance, wins, losses, draws, games, last_bet, client_seed, created_at, updated_at)
+		SELECT chat_id, balance, wins, losses, draws, games, last_bet, client_seed, created_at, updated_at
+		FROM players WHERE chat_id > 0;
+
+	DROP TABLE players;
+	ALTER TABLE players_new RENAME TO players;
+
+	CREATE INDEX idx_players_balance ON players(balance);
+	CREATE INDEX idx_players_games ON players(games);
+	`,
+	// пустой язык — еще не выбран, берется из настроек Telegram при первом сообщении
+	`
+	ALTER TABLE players ADD COLUMN language TEXT NOT NULL DEFAULT '';
+	`,
+	// токены HTTP API хранятся только хэшем
+	`
+	CREATE TABLE api_tokens (
+		token_hash TEXT PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX idx_api_tokens_user ON api_tokens(user_id);
+	`,
+	// ручные изменения баланса и модерация: кто, кому, на сколько и что стало
 	`
+	ALTER TABLE players ADD COLUMN banned INTEGER NOT NULL DEFAULT 0;
+
+	CREATE TABLE ledger (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		admin_id INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		amount INTEGER NOT NULL DEFAULT 0,
+		balance INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX idx_ledger_user ON ledger(user_id);
+	`,
+	// ежедневный бонус (день по UTC и серия) и время последней помощи банкроту
+	`
+	ALTER TABLE players ADD COLUMN bonus_day TEXT NOT NULL DEFAULT '';
+	ALTER TABLE players ADD COLUMN bonus_streak INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE players ADD COLUMN bailout_at INTEGER NOT NULL DEFAULT 0;
+	`,
+	// серия побед подряд и открытые достижения
+	`
+	ALTER TABLE players ADD COLUMN win_streak INTEGER NOT NULL DEFAULT 0;
+
+	CREATE TABLE achievements (
+		user_id INTEGER NOT NULL,
+		achievement TEXT NOT NULL,
+		unlocked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (user_id, achievement)
+	);
+	`,
+	// турниры: время в unix, призы за места через запятую; у участников свои фишки
+	`
+	CREATE TABLE tournaments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		starts_at INTEGER NOT NULL,
+		ends_at INTEGER NOT NULL,
+		rounds INTEGER NOT NULL,
+		chips INTEGER NOT NULL,
+		prizes TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		created_by INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX idx_tournaments_status ON tournaments(status);
+
+	CREATE TABLE tournament_players (
+		tournament_id INTEGER NOT NULL REFERENCES tournaments(id),
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL DEFAULT '',
+		balance INTEGER NOT NULL,
+		played INTEGER NOT NULL DEFAULT 0,
+		round_id INTEGER NOT NULL DEFAULT 0,
+		message_id INTEGER NOT NULL DEFAULT 0,
+		prize INTEGER NOT NULL DEFAULT 0,
+		joined_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (tournament_id, user_id)
+	);
+
+	CREATE INDEX idx_tournament_players_round ON tournament_players(round_id);
+	`,
+	// итоги побочных ставок раунда в JSON
+	`
+	ALTER TABLE rounds ADD COLUMN side_bets TEXT NOT NULL DEFAULT '';
+	`,
+	// любимые ставки игрока через запятую
+	`
+	ALTER TABLE players ADD COLUMN favorite_bets TEXT NOT NULL DEFAULT '';
+	`,
+	// игра картинкой стола вместо текста
+	`
+	ALTER TABLE players ADD COLUMN card_images INTEGER NOT NULL DEFAULT 0;
+	`,
+	// раунды HTTP API: после падения их доигрывает сервер API, а не бот
+	`
+	ALTER TABLE rounds ADD COLUMN api INTEGER NOT NULL DEFAULT 0;
+	`,
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
 
-	_, err := db.Exec(schema)
-	return err
+	return nil
 }
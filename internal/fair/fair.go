@@ -0,0 +1,76 @@
+package fair
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"blackjack/internal/game"
+)
+
+// GenerateServerSeed возвращает новый секретный сид сервера (32 байта в hex)
+func GenerateServerSeed() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate server seed: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Commit возвращает SHA-256 коммитмент сида, который публикуется до раунда
+func Commit(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Source — детерминированный поток чисел из SHA-256(server:client:nonce:counter).
+// Реализует rand.Source, поэтому его можно передать в game.NewDeck.
+type Source struct {
+	prefix  string
+	counter uint64
+	buf     []byte
+}
+
+func NewSource(serverSeed, clientSeed string, nonce int64) *Source {
+	return &Source{
+		prefix: serverSeed + ":" + clientSeed + ":" + strconv.FormatInt(nonce, 10) + ":",
+	}
+}
+
+func (s *Source) Uint64() uint64 {
+	if len(s.buf) < 8 {
+		sum := sha256.Sum256([]byte(s.prefix + strconv.FormatUint(s.counter, 10)))
+		s.counter++
+		s.buf = sum[:]
+	}
+
+	v := binary.BigEndian.Uint64(s.buf[:8])
+	s.buf = s.buf[8:]
+	return v
+}
+
+func (s *Source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Seed не поддерживается: поток полностью задается сидами
+func (s *Source) Seed(int64) {}
+
+// Verify проверяет, что сид соответствует коммитменту и что карты
+// были сданы из колоды варианта, перетасованной из сидов раунда
+func Verify(serverSeed, clientSeed string, nonce int64, commitment string, variant game.Variant, cards []string) error {
+	if Commit(serverSeed) != commitment {
+		return fmt.Errorf("server seed does not match commitment")
+	}
+
+	deck := game.NewVariantDeck(variant, NewSource(serverSeed, clientSeed, nonce))
+	for i, card := range cards {
+		if expected := deck.Draw(); !game.SameCard(expected, card) {
+			return fmt.Errorf("card %d: dealt %s, expected %s", i+1, card, expected)
+		}
+	}
+	return nil
+}
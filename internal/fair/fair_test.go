@@ -0,0 +1,100 @@
+package fair
+
+import (
+	"slices"
+	"testing"
+
+	"blackjack/internal/game"
+)
+
+const (
+	testServerSeed = "4f3c2a1b9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5e4d3c2b"
+	testClientSeed = "player-seed"
+)
+
+// play доигрывает раунд простой стратегией: сплит, когда можно, добор до 17
+func play(g *game.State) {
+	for {
+		hand := g.Current()
+		if hand == nil {
+			break
+		}
+		if hand.IsStand {
+			if !g.NextHand() {
+				break
+			}
+			continue
+		}
+
+		switch {
+		case g.CanSplit():
+			g.Split()
+		case hand.Score() < 17:
+			g.Hit()
+		default:
+			g.Stand()
+		}
+	}
+	g.Finish()
+}
+
+func TestVerifyRejectsTamperedSeed(t *testing.T) {
+	g := game.NewState([]int{100}, NewSource(testServerSeed, testClientSeed, 1))
+	play(g)
+	commitment := Commit(testServerSeed)
+
+	// последний символ сида заменен: коммитмент опубликован до раунда и уже не сходится
+	tampered := testServerSeed[:len(testServerSeed)-1] + "c"
+	if err := Verify(tampered, testClientSeed, 1, commitment, game.VariantClassic, g.Deck.Drawn()); err == nil {
+		t.Error("Verify() accepted a server seed that does not match the commitment")
+	}
+}
+
+func TestVerifySplitRound(t *testing.T) {
+	var g *game.State
+	var nonce int64
+	for nonce = 1; nonce < 1000; nonce++ {
+		g = game.NewState([]int{100}, NewSource(testServerSeed, testClientSeed, nonce))
+		if g.CanSplit() {
+			break
+		}
+	}
+	if !g.CanSplit() {
+		t.Fatal("no nonce deals a pair")
+	}
+	play(g)
+	dealt := g.Deck.Drawn()
+
+	// последняя карта с другой мастью; карты старых раундов записаны без мастей
+	other := slices.Clone(dealt)
+	last := other[len(other)-1]
+	if game.Suit(last) == "♠" {
+		other[len(other)-1] = game.Rank(last) + "♥"
+	} else {
+		other[len(other)-1] = game.Rank(last) + "♠"
+	}
+	ranks := make([]string, len(dealt))
+	for i, card := range dealt {
+		ranks[i] = game.Rank(card)
+	}
+
+	tests := []struct {
+		name  string
+		cards []string
+		ok    bool
+	}{
+		{name: "dealt cards", cards: dealt, ok: true},
+		{name: "card of another suit", cards: other, ok: false},
+		{name: "cards without suits", cards: ranks, ok: true},
+	}
+
+	commitment := Commit(testServerSeed)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(testServerSeed, testClientSeed, nonce, commitment, game.VariantClassic, tt.cards)
+			if (err == nil) != tt.ok {
+				t.Errorf("Verify() error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
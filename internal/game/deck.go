@@ -1,6 +1,11 @@
 package game
 
-import "math/rand"
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"strings"
+)
 
 var CardValues = map[string]int{
 	"2": 2, "3": 3, "4": 4, "5": 5, "6": 6, "7": 7, "8": 8, "9": 9, "10": 10,
@@ -9,39 +14,135 @@ var CardValues = map[string]int{
 
 var cardNames = []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "A"}
 
+// Масти идут в порядке колод в fill: раньше колода собиралась из четырех
+// копий cardNames без мастей, так что тасование и ранги не изменились,
+// и старые раунды проверяются по рангам
+var suits = []string{"♠", "♥", "♦", "♣"}
+
+// Rank — ранг карты без масти: "K♠" → "K". Карты старых раундов масти не имеют.
+func Rank(card string) string {
+	return strings.TrimRight(card, "♠♥♦♣")
+}
+
+// Suit — масть карты или пустая строка для карты без масти
+func Suit(card string) string {
+	return card[len(Rank(card)):]
+}
+
+// Value — очки карты, туз считается за 11
+func Value(card string) int {
+	return CardValues[Rank(card)]
+}
+
+// SameCard сравнивает сданную карту с записанной; записанная карта
+// без масти совпадает с любой картой того же ранга
+func SameCard(dealt, recorded string) bool {
+	return dealt == recorded || (Suit(recorded) == "" && Rank(dealt) == recorded)
+}
+
+// cryptoSource — rand.Source поверх crypto/rand, используется по умолчанию
+type cryptoSource struct{}
+
+func (cryptoSource) Int63() int64 {
+	return int64(cryptoSource{}.Uint64() >> 1)
+}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic("crypto/rand: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+func (cryptoSource) Seed(int64) {}
+
+// NewCryptoSource возвращает источник случайности на основе CSPRNG
+func NewCryptoSource() rand.Source {
+	return cryptoSource{}
+}
+
+// NewSeededSource возвращает детерминированный источник для тестов и повторов
+func NewSeededSource(seed int64) rand.Source {
+	return rand.NewSource(seed)
+}
+
 type Deck struct {
 	cards []string
+	drawn []string
+	rng   *rand.Rand
+
+	// ранги колоды; nil — все cardNames
+	ranks []string
 }
 
-func NewDeck() *Deck {
-	d := &Deck{
-		cards: make([]string, 0, 52),
+// NewDeck создает перетасованную колоду; если src == nil, берется CSPRNG
+func NewDeck(src rand.Source) *Deck {
+	if src == nil {
+		src = NewCryptoSource()
 	}
 
-	for i := 0; i < 4; i++ {
-		d.cards = append(d.cards, cardNames...)
+	d := &Deck{rng: rand.New(src)}
+	d.fill()
+	return d
+}
+
+// NewSpanishDeck создает испанскую колоду из 48 карт, без десяток
+func NewSpanishDeck(src rand.Source) *Deck {
+	if src == nil {
+		src = NewCryptoSource()
 	}
 
-	d.Shuffle()
+	d := &Deck{rng: rand.New(src), ranks: spanishNames}
+	d.fill()
+	return d
+}
+
+// NewStackedDeck создает колоду, из которой карты выходят в заданном порядке.
+// Когда они заканчиваются, колода пополняется обычным тасованием.
+func NewStackedDeck(cards ...string) *Deck {
+	d := &Deck{rng: rand.New(NewCryptoSource())}
+	d.cards = append(make([]string, 0, len(cards)), cards...)
 	return d
 }
 
+func (d *Deck) fill() {
+	ranks := d.ranks
+	if ranks == nil {
+		ranks = cardNames
+	}
+
+	d.cards = make([]string, 0, len(suits)*len(ranks))
+	for _, suit := range suits {
+		for _, name := range ranks {
+			d.cards = append(d.cards, name+suit)
+		}
+	}
+	d.Shuffle()
+}
+
 func (d *Deck) Shuffle() {
-	rand.Shuffle(len(d.cards), func(i, j int) {
+	d.rng.Shuffle(len(d.cards), func(i, j int) {
 		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
 	})
 }
 
 func (d *Deck) Draw() string {
 	if len(d.cards) == 0 {
-		*d = *NewDeck()
+		d.fill()
 	}
 
 	card := d.cards[0]
 	d.cards = d.cards[1:]
+	d.drawn = append(d.drawn, card)
 	return card
 }
 
+// Drawn возвращает все сданные карты по порядку
+func (d *Deck) Drawn() []string {
+	return append([]string(nil), d.drawn...)
+}
+
 func (d *Deck) Remaining() int {
 	return len(d.cards)
 }
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func draw(d *Deck, n int) []string {
+	cards := make([]string, n)
+	for i := range cards {
+		cards[i] = d.Draw()
+	}
+	return cards
+}
+
+func TestDeckOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		deck func() *Deck
+		want []string
+	}{
+		{
+			name: "seeded deck repeats its order",
+			deck: func() *Deck { return NewDeck(NewSeededSource(42)) },
+			want: draw(NewDeck(NewSeededSource(42)), 52),
+		},
+		{
+			name: "seeded spanish deck repeats its order",
+			deck: func() *Deck { return NewSpanishDeck(NewSeededSource(7)) },
+			want: draw(NewSpanishDeck(NewSeededSource(7)), 48),
+		},
+		{
+			name: "stacked deck deals chosen cards",
+			deck: func() *Deck { return NewStackedDeck("A♠", "K♥", "10♦", "5♣") },
+			want: []string{"A♠", "K♥", "10♦", "5♣"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.deck()
+			if got := draw(d, len(tt.want)); !slices.Equal(got, tt.want) {
+				t.Errorf("dealt %v, want %v", got, tt.want)
+			}
+			if got := d.Drawn(); !slices.Equal(got, tt.want) {
+				t.Errorf("Drawn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
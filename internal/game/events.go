@@ -0,0 +1,127 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type EventType string
+
+const (
+	EventBet        EventType = "bet"
+	EventDeal       EventType = "deal"
+	EventHit        EventType = "hit"
+	EventStand      EventType = "stand"
+	EventDouble     EventType = "double"
+	EventSplit      EventType = "split"
+	EventSurrender  EventType = "surrender"
+	EventForfeit    EventType = "forfeit"
+	EventFinish     EventType = "finish"
+	EventDealerDraw EventType = "dealer_draw"
+	EventSwitch     EventType = "switch"
+)
+
+// DealerHand — индекс руки в событиях, относящихся к дилеру
+const DealerHand = -1
+
+// Event — одно изменение состояния игры. Действия игрока (hit, stand, double,
+// split, surrender, forfeit, switch, finish) можно повторить, остальные события — их результат.
+type Event struct {
+	Type EventType `json:"type"`
+	Hand int       `json:"hand"`
+	Card string    `json:"card,omitempty"`
+	Bet  int       `json:"bet,omitempty"`
+
+	// вариант и правила стола, только у ставок
+	Variant Variant `json:"variant,omitempty"`
+	Rules   Rules   `json:"rules,omitzero"`
+}
+
+// sameEvent сравнивает событие повтора с записанным; карты старых
+// раундов записаны без масти
+func sameEvent(replayed, recorded Event) bool {
+	if !SameCard(replayed.Card, recorded.Card) {
+		return false
+	}
+	replayed.Card = recorded.Card
+	return replayed == recorded
+}
+
+func (s *State) record(e Event) {
+	s.Events = append(s.Events, e)
+	if s.OnEvent != nil {
+		s.OnEvent(e)
+	}
+}
+
+// Replay восстанавливает игру из журнала событий и колоды, перетасованной из src.
+// Каждое событие сверяется с тем, что получилось при повторе; onStep (если задан)
+// вызывается после каждого сверенного события.
+func Replay(src rand.Source, events []Event, onStep func(Event, *State)) (*State, error) {
+	var bets []int
+	for _, ev := range events {
+		if ev.Type != EventBet {
+			break
+		}
+		bets = append(bets, ev.Bet)
+	}
+	if len(bets) == 0 {
+		return nil, fmt.Errorf("event log must start with a bet")
+	}
+
+	s := NewRulesState(bets, events[0].Variant, events[0].Rules, src)
+
+	i := 0
+	check := func() error {
+		for ; i < len(s.Events); i++ {
+			if i >= len(events) || !sameEvent(s.Events[i], events[i]) {
+				return fmt.Errorf("step %d: replay diverged from event log", i+1)
+			}
+			if onStep != nil {
+				onStep(events[i], s)
+			}
+		}
+		return nil
+	}
+
+	if err := check(); err != nil {
+		return nil, err
+	}
+
+	for i < len(events) {
+		ev := events[i]
+		if ev.Type != EventFinish {
+			if ev.Hand < 0 || ev.Hand >= len(s.Hands) {
+				return nil, fmt.Errorf("step %d: no hand %d", i+1, ev.Hand)
+			}
+			s.CurrentHand = ev.Hand
+		}
+
+		switch ev.Type {
+		case EventHit:
+			s.Hit()
+		case EventStand:
+			s.Stand()
+		case EventDouble:
+			s.Double()
+		case EventSplit:
+			s.Split()
+		case EventSurrender:
+			s.Surrender()
+		case EventForfeit:
+			s.Forfeit()
+		case EventSwitch:
+			s.SwitchCards()
+		case EventFinish:
+			s.Finish()
+		default:
+			return nil, fmt.Errorf("step %d: unexpected %s event", i+1, ev.Type)
+		}
+
+		if err := check(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+// playRound доигрывает раунд простой стратегией: сплит, когда можно, добор до 17
+func playRound(s *State) {
+	for {
+		hand := s.Current()
+		if hand == nil {
+			break
+		}
+		if hand.IsStand {
+			if !s.NextHand() {
+				break
+			}
+			continue
+		}
+
+		switch {
+		case s.CanSplit():
+			s.Split()
+		case hand.Score() < 17:
+			s.Hit()
+		default:
+			s.Stand()
+		}
+	}
+	s.Finish()
+}
+
+func TestReplayRestoresRound(t *testing.T) {
+	tests := []struct {
+		name string
+		seed int64
+		bets []int
+	}{
+		{name: "one box", seed: 1, bets: []int{100}},
+		{name: "two boxes", seed: 2, bets: []int{100, 50}},
+		{name: "three boxes", seed: 3, bets: []int{10, 20, 30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewState(tt.bets, NewSeededSource(tt.seed))
+			playRound(s)
+
+			r, err := Replay(NewSeededSource(tt.seed), s.Events, nil)
+			if err != nil {
+				t.Fatalf("Replay() error: %v", err)
+			}
+
+			if !slices.Equal(r.DealerCards, s.DealerCards) {
+				t.Errorf("dealer cards %v, want %v", r.DealerCards, s.DealerCards)
+			}
+			if len(r.Hands) != len(s.Hands) {
+				t.Fatalf("%d hands, want %d", len(r.Hands), len(s.Hands))
+			}
+			for i, hand := range s.Hands {
+				if !slices.Equal(r.Hands[i].Cards, hand.Cards) || r.Hands[i].Bet != hand.Bet {
+					t.Errorf("hand %d: %v bet %d, want %v bet %d", i+1, r.Hands[i].Cards, r.Hands[i].Bet, hand.Cards, hand.Bet)
+				}
+			}
+			if r.IsActive {
+				t.Error("replayed round is still active")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package game
+
+// Free Bet: удвоение на жестких 9–11 и сплит любых пар, кроме десяток, идут
+// бесплатными фишками — они приносят только выигрыш, без возврата ставки.
+// Зато 22 у дилера — ничья для всех оставшихся рук.
+
+// Real — часть ставки руки, оплаченная игроком
+func (h *Hand) Real() int {
+	return h.Bet - h.Free
+}
+
+func (s *State) FreeBet() bool {
+	return s.Variant == VariantFreeBet
+}
+
+// freeDouble — удвоение текущей руки бесплатное: жесткие 9, 10 или 11 на двух картах
+func (s *State) freeDouble(hand *Hand) bool {
+	if !s.FreeBet() || !hand.CanDouble() || IsSoft(hand.Cards) {
+		return false
+	}
+	score := hand.Score()
+	return score >= 9 && score <= 11
+}
+
+// freeSplit — сплит текущей руки бесплатный: любая пара, кроме десяток и картинок
+func (s *State) freeSplit(hand *Hand) bool {
+	return s.FreeBet() && hand.CanSplit() && Value(hand.Cards[0]) != 10
+}
+
+// DoubleCost — сколько игрок доплачивает за удвоение текущей руки
+func (s *State) DoubleCost() int {
+	hand := s.Current()
+	if hand == nil || s.freeDouble(hand) {
+		return 0
+	}
+	return hand.Bet
+}
+
+// SplitCost — сколько игрок доплачивает за сплит текущей руки
+func (s *State) SplitCost() int {
+	hand := s.Current()
+	if hand == nil || s.freeSplit(hand) {
+		return 0
+	}
+	return hand.Bet
+}
@@ -0,0 +1,64 @@
+package game
+
+import "testing"
+
+func TestFreeBetCosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		cards   []string
+		double  int
+		split   int
+	}{
+		{name: "hard 9", variant: VariantFreeBet, cards: []string{"5♠", "4♥"}, double: 0, split: 100},
+		{name: "hard 11", variant: VariantFreeBet, cards: []string{"6♠", "5♥"}, double: 0, split: 100},
+		{name: "hard 12", variant: VariantFreeBet, cards: []string{"7♠", "5♥"}, double: 100, split: 100},
+		{name: "soft 13", variant: VariantFreeBet, cards: []string{"A♠", "2♥"}, double: 100, split: 100},
+		{name: "pair of fives", variant: VariantFreeBet, cards: []string{"5♠", "5♥"}, double: 0, split: 0},
+		{name: "pair of eights", variant: VariantFreeBet, cards: []string{"8♠", "8♥"}, double: 100, split: 0},
+		{name: "pair of aces", variant: VariantFreeBet, cards: []string{"A♠", "A♥"}, double: 100, split: 0},
+		{name: "ten and king", variant: VariantFreeBet, cards: []string{"10♠", "K♥"}, double: 100, split: 100},
+		{name: "classic pair of fives", variant: VariantClassic, cards: []string{"5♠", "5♥"}, double: 100, split: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &State{Variant: tt.variant, Hands: []*Hand{{Cards: tt.cards, Bet: 100}}}
+			if got := s.DoubleCost(); got != tt.double {
+				t.Errorf("DoubleCost() = %d, want %d", got, tt.double)
+			}
+			if got := s.SplitCost(); got != tt.split {
+				t.Errorf("SplitCost() = %d, want %d", got, tt.split)
+			}
+		})
+	}
+}
+
+func TestFreeBetResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		hand    Hand
+		dealer  []string
+		result  Result
+		payout  int
+	}{
+		{name: "dealer 22 pushes", variant: VariantFreeBet, hand: Hand{Cards: []string{"K♠", "Q♥"}, Bet: 100}, dealer: []string{"10♠", "6♥", "6♦"}, result: ResultPush, payout: 100},
+		{name: "dealer 22 returns only the paid part", variant: VariantFreeBet, hand: Hand{Cards: []string{"5♠", "5♥", "K♦"}, Bet: 200, Free: 100, IsDouble: true}, dealer: []string{"10♠", "6♥", "6♦"}, result: ResultPush, payout: 100},
+		{name: "bust still loses to dealer 22", variant: VariantFreeBet, hand: Hand{Cards: []string{"K♠", "6♥", "9♦"}, Bet: 100, IsBust: true}, dealer: []string{"10♠", "6♥", "6♦"}, result: ResultDealerWin, payout: 0},
+		{name: "blackjack beats dealer 22", variant: VariantFreeBet, hand: Hand{Cards: []string{"A♠", "K♥"}, Bet: 100}, dealer: []string{"10♠", "6♥", "6♦"}, result: ResultBlackjack, payout: 250},
+		{name: "dealer 23 busts", variant: VariantFreeBet, hand: Hand{Cards: []string{"K♠", "Q♥"}, Bet: 100}, dealer: []string{"10♠", "6♥", "7♦"}, result: ResultPlayerWin, payout: 200},
+		{name: "free double wins the whole bet", variant: VariantFreeBet, hand: Hand{Cards: []string{"5♠", "5♥", "K♦"}, Bet: 200, Free: 100, IsDouble: true}, dealer: []string{"10♠", "8♥"}, result: ResultPlayerWin, payout: 300},
+		{name: "classic dealer 22 busts", variant: VariantClassic, hand: Hand{Cards: []string{"K♠", "Q♥"}, Bet: 100}, dealer: []string{"10♠", "6♥", "6♦"}, result: ResultPlayerWin, payout: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &State{Variant: tt.variant, DealerCards: tt.dealer, Hands: []*Hand{&tt.hand}, BlackjackPays: 2.5}
+			result, payout := s.HandResult(&tt.hand)
+			if result != tt.result || payout != tt.payout {
+				t.Errorf("HandResult() = %s, %d, want %s, %d", result.Name(), payout, tt.result.Name(), tt.payout)
+			}
+		})
+	}
+}
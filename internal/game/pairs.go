@@ -0,0 +1,57 @@
+package game
+
+import "slices"
+
+// PairHand — пара из двух первых карт для побочной ставки Perfect Pairs
+type PairHand string
+
+const (
+	PairNone     PairHand = ""
+	PairMixed    PairHand = "mixed"
+	PairColoured PairHand = "coloured"
+	PairPerfect  PairHand = "perfect"
+)
+
+// PairHands — выигрышные пары от младшей к старшей
+var PairHands = []PairHand{PairMixed, PairColoured, PairPerfect}
+
+// PairPaytable — выплаты «к одному» по парам; ставка возвращается сверху
+type PairPaytable map[PairHand]int
+
+// Payout — выигрыш вместе со ставкой; 0, если пары нет
+func (p PairPaytable) Payout(hand PairHand, bet int) int {
+	if hand == PairNone {
+		return 0
+	}
+	return bet * (p[hand] + 1)
+}
+
+// red — червы и бубны; остальные масти черные
+var red = []string{"♥", "♦"}
+
+// PerfectPairs оценивает две карты: пара одного ранга разного цвета, одного
+// цвета или одной масти. Пара карт без масти считается разноцветной.
+func PerfectPairs(cards []string) PairHand {
+	if len(cards) != 2 || Rank(cards[0]) != Rank(cards[1]) {
+		return PairNone
+	}
+
+	a, b := Suit(cards[0]), Suit(cards[1])
+	switch {
+	case a == "" || b == "":
+		return PairMixed
+	case a == b:
+		return PairPerfect
+	case slices.Contains(red, a) == slices.Contains(red, b):
+		return PairColoured
+	}
+	return PairMixed
+}
+
+// PairCards — две первые карты бокса для Perfect Pairs
+func (s *State) PairCards(box int) []string {
+	if cards := s.SideCards(box); cards != nil {
+		return cards[:2]
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package game
+
+import "testing"
+
+func TestPerfectPairs(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []string
+		want  PairHand
+	}{
+		{name: "no pair", cards: []string{"8♠", "9♠"}, want: PairNone},
+		{name: "same value, different rank", cards: []string{"K♠", "Q♠"}, want: PairNone},
+		{name: "mixed", cards: []string{"8♠", "8♥"}, want: PairMixed},
+		{name: "coloured black", cards: []string{"8♠", "8♣"}, want: PairColoured},
+		{name: "coloured red", cards: []string{"A♥", "A♦"}, want: PairColoured},
+		{name: "perfect", cards: []string{"10♦", "10♦"}, want: PairPerfect},
+		{name: "cards without suits are mixed", cards: []string{"8", "8♠"}, want: PairMixed},
+		{name: "three cards", cards: []string{"8♠", "8♠", "8♠"}, want: PairNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PerfectPairs(tt.cards); got != tt.want {
+				t.Errorf("PerfectPairs(%v) = %q, want %q", tt.cards, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPairPaytablePayout(t *testing.T) {
+	p := PairPaytable{PairMixed: 5, PairColoured: 10, PairPerfect: 30}
+
+	tests := []struct {
+		hand PairHand
+		want int
+	}{
+		{hand: PairNone, want: 0},
+		{hand: PairMixed, want: 60},
+		{hand: PairColoured, want: 110},
+		{hand: PairPerfect, want: 310},
+	}
+
+	for _, tt := range tests {
+		if got := p.Payout(tt.hand, 10); got != tt.want {
+			t.Errorf("Payout(%q, 10) = %d, want %d", tt.hand, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package game
+
+import "slices"
+
+// PokerHand — покерная комбинация из трех карт для побочной ставки 21+3
+type PokerHand string
+
+const (
+	PokerNone          PokerHand = ""
+	PokerFlush         PokerHand = "flush"
+	PokerStraight      PokerHand = "straight"
+	PokerThreeOfAKind  PokerHand = "three_of_a_kind"
+	PokerStraightFlush PokerHand = "straight_flush"
+	PokerSuitedTrips   PokerHand = "suited_trips"
+)
+
+// PokerHands — выигрышные комбинации от младшей к старшей
+var PokerHands = []PokerHand{PokerFlush, PokerStraight, PokerThreeOfAKind, PokerStraightFlush, PokerSuitedTrips}
+
+// Paytable — выплаты «к одному» по комбинациям; ставка возвращается сверху
+type Paytable map[PokerHand]int
+
+// Payout — выигрыш вместе со ставкой; 0, если комбинации нет
+func (p Paytable) Payout(hand PokerHand, bet int) int {
+	if hand == PokerNone {
+		return 0
+	}
+	return bet * (p[hand] + 1)
+}
+
+// ThreeCardPoker оценивает три карты как покерную руку: туз бывает и старшим
+// (Q-K-A), и младшим (A-2-3). Карты без масти флеша не дают.
+func ThreeCardPoker(cards []string) PokerHand {
+	if len(cards) != 3 {
+		return PokerNone
+	}
+
+	suit := Suit(cards[0])
+	flush := suit != ""
+	ranks := make([]int, len(cards))
+	for i, card := range cards {
+		if Suit(card) != suit {
+			flush = false
+		}
+		ranks[i] = slices.Index(cardNames, Rank(card))
+	}
+	slices.Sort(ranks)
+
+	trips := ranks[0] == ranks[2]
+	straight := ranks[1] == ranks[0]+1 && ranks[2] == ranks[1]+1
+	// A-2-3: туз стоит последним в cardNames
+	if ranks[0] == 0 && ranks[1] == 1 && ranks[2] == len(cardNames)-1 {
+		straight = true
+	}
+
+	switch {
+	case trips && flush:
+		return PokerSuitedTrips
+	case straight && flush:
+		return PokerStraightFlush
+	case trips:
+		return PokerThreeOfAKind
+	case straight:
+		return PokerStraight
+	case flush:
+		return PokerFlush
+	}
+	return PokerNone
+}
+
+// SideCards — карты для 21+3 бокса: две первые карты игрока и открытая карта дилера.
+// Берутся сразу после раздачи, пока сплит не изменил руку.
+func (s *State) SideCards(box int) []string {
+	for _, hand := range s.Hands {
+		if hand.Box == box && len(hand.Cards) >= 2 {
+			return []string{hand.Cards[0], hand.Cards[1], s.DealerCards[0]}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package game
+
+import "testing"
+
+func TestThreeCardPoker(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []string
+		want  PokerHand
+	}{
+		{name: "nothing", cards: []string{"2♠", "7♥", "K♦"}, want: PokerNone},
+		{name: "flush", cards: []string{"2♥", "7♥", "K♥"}, want: PokerFlush},
+		{name: "straight", cards: []string{"9♠", "10♥", "J♦"}, want: PokerStraight},
+		{name: "ace low straight", cards: []string{"3♣", "A♠", "2♥"}, want: PokerStraight},
+		{name: "ace high straight", cards: []string{"K♦", "Q♠", "A♥"}, want: PokerStraight},
+		{name: "no wrap around the ace", cards: []string{"K♠", "A♥", "2♦"}, want: PokerNone},
+		{name: "three of a kind", cards: []string{"7♠", "7♥", "7♦"}, want: PokerThreeOfAKind},
+		{name: "straight flush", cards: []string{"4♣", "5♣", "6♣"}, want: PokerStraightFlush},
+		{name: "ace low straight flush", cards: []string{"A♦", "2♦", "3♦"}, want: PokerStraightFlush},
+		{name: "suited trips", cards: []string{"Q♠", "Q♠", "Q♠"}, want: PokerSuitedTrips},
+		{name: "cards without suits make no flush", cards: []string{"2", "7", "K"}, want: PokerNone},
+		{name: "two cards", cards: []string{"7♠", "7♥"}, want: PokerNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ThreeCardPoker(tt.cards); got != tt.want {
+				t.Errorf("ThreeCardPoker(%v) = %q, want %q", tt.cards, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaytablePayout(t *testing.T) {
+	p := Paytable{PokerFlush: 5, PokerStraight: 10, PokerSuitedTrips: 100}
+
+	tests := []struct {
+		hand PokerHand
+		want int
+	}{
+		{hand: PokerNone, want: 0},
+		{hand: PokerFlush, want: 60},
+		{hand: PokerStraight, want: 110},
+		{hand: PokerSuitedTrips, want: 1010},
+	}
+
+	for _, tt := range tests {
+		if got := p.Payout(tt.hand, 10); got != tt.want {
+			t.Errorf("Payout(%q, 10) = %d, want %d", tt.hand, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rules — необязательные правила стола поверх варианта
+type Rules struct {
+	// N-card Charlie: рука из Charlie карт без перебора выигрывает сразу; 0 — выключено
+	Charlie int `json:"charlie,omitempty"`
+
+	// 21 игрока бьет 21 дилера
+	Player21Wins bool `json:"player_21_wins,omitempty"`
+
+	// при равенстве очков выигрывает дилер
+	DealerWinsTies bool `json:"dealer_wins_ties,omitempty"`
+}
+
+// Допустимые размеры Charlie
+const (
+	MinCharlie = 5
+	MaxCharlie = 7
+)
+
+// Имена правил в командах: charlie=5, 21wins, ties
+const (
+	RuleCharlie        = "charlie"
+	RulePlayer21Wins   = "21wins"
+	RuleDealerWinsTies = "ties"
+)
+
+// ParseRules читает правила из слов вида "charlie=5 21wins ties"; "off" — без правил
+func ParseRules(words []string) (Rules, error) {
+	var r Rules
+	for _, w := range words {
+		name, value, _ := strings.Cut(strings.ToLower(strings.TrimSpace(w)), "=")
+		switch name {
+		case "", "off":
+		case RuleCharlie:
+			n, err := strconv.Atoi(value)
+			if err != nil || n < MinCharlie || n > MaxCharlie {
+				return Rules{}, fmt.Errorf("charlie must be between %d and %d", MinCharlie, MaxCharlie)
+			}
+			r.Charlie = n
+		case RulePlayer21Wins:
+			r.Player21Wins = true
+		case RuleDealerWinsTies:
+			r.DealerWinsTies = true
+		default:
+			return Rules{}, fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	return r, nil
+}
+
+// Reason — почему рука выиграла или проиграла не по обычному сравнению очков
+type Reason string
+
+const (
+	ReasonNone         Reason = ""
+	ReasonCharlie      Reason = "charlie"
+	ReasonPlayer21Wins Reason = "21wins"
+	ReasonDealerTies   Reason = "ties"
+)
+
+// charlie — рука набрала Charlie карт без перебора
+func (s *State) charlie(hand *Hand) bool {
+	return s.Rules.Charlie > 0 && !hand.IsBust && len(hand.Cards) >= s.Rules.Charlie
+}
+
+// ResultReason объясняет итог руки, если его решило правило стола
+func (s *State) ResultReason(hand *Hand) Reason {
+	_, _, reason := s.handResult(hand)
+	return reason
+}
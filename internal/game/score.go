@@ -5,8 +5,8 @@ func CalculateScore(hand []string) int {
 	aces := 0
 
 	for _, card := range hand {
-		score += CardValues[card]
-		if card == "A" {
+		score += Value(card)
+		if Rank(card) == "A" {
 			aces++
 		}
 	}
@@ -19,6 +19,22 @@ func CalculateScore(hand []string) int {
 	return score
 }
 
+// IsSoft — туз в руке считается за 11
+func IsSoft(cards []string) bool {
+	score, aces := 0, 0
+	for _, card := range cards {
+		score += Value(card)
+		if Rank(card) == "A" {
+			aces++
+		}
+	}
+	for score > 21 && aces > 0 {
+		score -= 10
+		aces--
+	}
+	return aces > 0
+}
+
 func IsBlackjack(cards []string) bool {
 	if len(cards) != 2 {
 		return false
@@ -30,10 +46,10 @@ func IsBlackjack(cards []string) bool {
 
 	hasAce, hasTen := false, false
 	for _, card := range cards {
-		if card == "A" {
+		switch Rank(card) {
+		case "A":
 			hasAce = true
-		}
-		if card == "10" || card == "J" || card == "Q" || card == "K" {
+		case "10", "J", "Q", "K":
 			hasTen = true
 		}
 	}
@@ -0,0 +1,20 @@
+package game
+
+// SideKind — вид побочной ставки
+type SideKind string
+
+const (
+	SidePlus3 SideKind = "21+3"
+	SidePairs SideKind = "perfect_pairs"
+)
+
+// SideResult — итог побочной ставки одного бокса. Hand — комбинация
+// (PokerHand или PairHand), пустая при проигрыше; Win — выигрыш со ставкой.
+type SideResult struct {
+	Kind  SideKind `json:"kind"`
+	Box   int      `json:"box"`
+	Cards []string `json:"cards"`
+	Hand  string   `json:"hand,omitempty"`
+	Bet   int      `json:"bet"`
+	Win   int      `json:"win,omitempty"`
+}
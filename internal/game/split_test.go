@@ -0,0 +1,142 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func handCards(s *State) [][]string {
+	cards := make([][]string, len(s.Hands))
+	for i, h := range s.Hands {
+		cards[i] = h.Cards
+	}
+	return cards
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		cards  []string // игрок, игрок, дилер, дилер, затем добор после сплитов
+		splits int
+		want   [][]string
+		stand  bool // руки после сплитов закрыты
+		more   bool // можно ли сплитовать дальше
+	}{
+		{
+			name:   "re-split up to four hands",
+			cards:  []string{"K♠", "Q♥", "9♣", "7♦", "J♦", "5♣", "10♣", "6♠", "Q♠", "4♥"},
+			splits: 3,
+			want:   [][]string{{"K♠", "Q♠"}, {"10♣", "4♥"}, {"J♦", "6♠"}, {"Q♥", "5♣"}},
+			more:   false,
+		},
+		{
+			name:   "second pair can be split again",
+			cards:  []string{"8♠", "8♥", "9♣", "7♦", "8♦", "2♣"},
+			splits: 1,
+			want:   [][]string{{"8♠", "8♦"}, {"8♥", "2♣"}},
+			more:   true,
+		},
+		{
+			name:   "split aces get one card each",
+			cards:  []string{"A♠", "A♥", "9♣", "7♦", "A♦", "K♣"},
+			splits: 1,
+			want:   [][]string{{"A♠", "A♦"}, {"A♥", "K♣"}},
+			stand:  true,
+			more:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStateWithDeck([]int{100}, NewStackedDeck(tt.cards...))
+			for i := range tt.splits {
+				if !s.Split() {
+					t.Fatalf("split %d refused", i+1)
+				}
+			}
+
+			if got := handCards(s); !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("hands %v, want %v", got, tt.want)
+			}
+			for i, h := range s.Hands {
+				if h.IsStand != tt.stand {
+					t.Errorf("hand %d: IsStand = %v, want %v", i+1, h.IsStand, tt.stand)
+				}
+				if h.Bet != 100 || h.IsBlackjack() {
+					t.Errorf("hand %d: bet %d, blackjack %v", i+1, h.Bet, h.IsBlackjack())
+				}
+			}
+			if s.CanSplit() != tt.more {
+				t.Errorf("CanSplit() = %v, want %v", s.CanSplit(), tt.more)
+			}
+			if !tt.more && s.Split() {
+				t.Error("Split() went past the limit")
+			}
+		})
+	}
+}
+
+// splitRound играет сплит на первой паре, которую даст сид, и доигрывает раунд стойкой
+func splitRound(t *testing.T) (*State, int64) {
+	t.Helper()
+	for seed := int64(1); seed < 1000; seed++ {
+		s := NewState([]int{100}, NewSeededSource(seed))
+		if !s.CanSplit() {
+			continue
+		}
+
+		s.Split()
+		for s.Current() != nil {
+			if !s.Current().IsStand {
+				s.Stand()
+			}
+			s.NextHand()
+		}
+		s.Finish()
+		return s, seed
+	}
+	t.Fatal("no seed deals a pair")
+	return nil, 0
+}
+
+func TestReplaySplitRoundChecksSuits(t *testing.T) {
+	s, seed := splitRound(t)
+
+	r, err := Replay(NewSeededSource(seed), s.Events, nil)
+	if err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+	if got := handCards(r); !slices.EqualFunc(got, handCards(s), slices.Equal) {
+		t.Errorf("replayed hands %v, want %v", got, handCards(s))
+	}
+	if !slices.Equal(r.DealerCards, s.DealerCards) {
+		t.Errorf("replayed dealer %v, want %v", r.DealerCards, s.DealerCards)
+	}
+
+	// карта первой добранной после сплита позиции — с другой мастью и без масти
+	i := slices.IndexFunc(s.Events, func(e Event) bool { return e.Type == EventSplit }) + 1
+	card := s.Events[i].Card
+	other := "♠"
+	if Suit(card) == other {
+		other = "♥"
+	}
+
+	tests := []struct {
+		name string
+		card string
+		ok   bool
+	}{
+		{name: "other suit diverges", card: Rank(card) + other, ok: false},
+		{name: "card without suit from old rounds", card: Rank(card), ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := slices.Clone(s.Events)
+			events[i].Card = tt.card
+			if _, err := Replay(NewSeededSource(seed), events, nil); (err == nil) != tt.ok {
+				t.Errorf("Replay() error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
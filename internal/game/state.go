@@ -1,28 +1,55 @@
 package game
 
 import (
+	"math/rand"
 	"sync"
+	"time"
 )
 
 type Result int
 
+// MaxSplitHands — сколько рук может получиться из одного бокса сплитами
+const MaxSplitHands = 4
+
 const (
 	ResultNone Result = iota
 	ResultPlayerWin
 	ResultDealerWin
 	ResultPush
 	ResultBlackjack
+	ResultSurrender
+	// 21 с бонусом испанского варианта
+	ResultBonus
 )
 
+var resultNames = map[Result]string{
+	ResultPlayerWin: "win",
+	ResultDealerWin: "loss",
+	ResultPush:      "push",
+	ResultBlackjack: "blackjack",
+	ResultSurrender: "surrender",
+	ResultBonus:     "bonus",
+}
+
+// Name — итог руки для API и трансляции: "win", "loss"...; у ResultNone пустой
+func (r Result) Name() string {
+	return resultNames[r]
+}
+
 // рука для сплита
 type Hand struct {
+	Box       int
 	Cards     []string
 	Bet       int
+	Free      int // бесплатная часть Bet в Free Bet
 	IsStand   bool
 	IsDouble  bool
 	IsBust    bool
 	FromSplit bool
 	SplitAces bool
+
+	IsSurrender bool
+	IsForfeit   bool
 }
 
 func NewHand(bet int) *Hand {
@@ -36,18 +63,25 @@ func (h *Hand) Score() int {
 	return CalculateScore(h.Cards)
 }
 
+// CanSplit — пара одного достоинства; пересплит разрешен, кроме тузов.
+// Сколько рук уже в боксе, проверяет State.CanSplit.
 func (h *Hand) CanSplit() bool {
-	if len(h.Cards) != 2 || h.FromSplit {
+	if len(h.Cards) != 2 || h.SplitAces {
 		return false
 	}
 	// проверка карт на одинаковость
-	return CardValues[h.Cards[0]] == CardValues[h.Cards[1]]
+	return Value(h.Cards[0]) == Value(h.Cards[1])
 }
 
 func (h *Hand) CanDouble() bool {
 	return len(h.Cards) == 2 && !h.IsDouble && !h.SplitAces
 }
 
+// needsDealer — рука еще сравнивается с дилером, которому нужно добирать
+func (h *Hand) needsDealer() bool {
+	return !h.IsBust && !h.IsBlackjack() && !h.IsSurrender && !h.IsForfeit
+}
+
 func (h *Hand) IsBlackjack() bool {
 	// после сплита блэкджек не работает
 	if h.FromSplit {
@@ -58,37 +92,116 @@ func (h *Hand) IsBlackjack() bool {
 
 // Храним состояние игры
 type State struct {
+	mu sync.Mutex
+
 	Hands       []*Hand
 	DealerCards []string
 	Deck        *Deck
 	CurrentHand int
 	IsActive    bool
-	InitialBet  int
+	InitialBets []int
+	RoundID     int64
+	Variant     Variant
+	Rules       Rules
+
+	// побочные ставки 21+3 и Perfect Pairs на каждый бокс; рассчитываются
+	// сразу после раздачи, итоги хранятся в SideResults
+	SideBet     int
+	PairsBet    int
+	SideResults []SideResult
+
+	Events []Event
+
+	// выплата за блэкджек вместе со ставкой
+	BlackjackPays float64
+
+	// кто играет, последнее сообщение с кнопками и время последнего хода
+	PlayerID   int64
+	MessageID  int
+	LastAction time.Time
+
+	// игра показывается картинкой стола с текстом в подписи
+	Images bool
+
+	// турнир, на фишки которого идет игра; 0 — основной баланс
+	TournamentID int64
+
+	// OnEvent вызывается на каждое новое событие, например для трансляции
+	OnEvent func(Event)
+}
+
+func (s *State) Lock()   { s.mu.Lock() }
+func (s *State) Unlock() { s.mu.Unlock() }
+
+// NewState раздает новую игру на один или несколько боксов;
+// src задает тасование колоды (nil — CSPRNG)
+func NewState(bets []int, src rand.Source) *State {
+	return NewVariantState(bets, VariantClassic, src)
+}
+
+// NewStateWithDeck раздает игру из готовой колоды, например из NewStackedDeck
+func NewStateWithDeck(bets []int, deck *Deck) *State {
+	return newState(bets, VariantClassic, Rules{}, deck)
 }
 
-func NewState(bet int) *State {
+func newState(bets []int, variant Variant, rules Rules, deck *Deck) *State {
 	s := &State{
-		Deck:        NewDeck(),
-		Hands:       make([]*Hand, 0, 4),
-		DealerCards: make([]string, 0, 10),
-		CurrentHand: 0,
-		IsActive:    true,
-		InitialBet:  bet,
+		Deck:          deck,
+		Hands:         make([]*Hand, 0, 4*len(bets)),
+		DealerCards:   make([]string, 0, 10),
+		CurrentHand:   0,
+		IsActive:      true,
+		InitialBets:   append([]int(nil), bets...),
+		Variant:       variant,
+		Rules:         rules,
+		BlackjackPays: 2.5,
+		LastAction:    time.Now(),
+	}
+
+	// по руке на каждый бокс, все боксы получают карты раньше дилера;
+	// вариант и правила пишутся в ставки, чтобы повтор раздал ту же колоду
+	// и сыграл руки так же
+	for box, bet := range bets {
+		s.record(Event{Type: EventBet, Hand: box, Bet: bet, Variant: variant, Rules: rules})
+
+		hand := NewHand(bet)
+		hand.Box = box
+		s.Hands = append(s.Hands, hand)
 	}
 
-	// создаем новую первую руку
-	hand := NewHand(bet)
-	hand.Cards = append(hand.Cards, s.Deck.Draw(), s.Deck.Draw())
-	s.Hands = append(s.Hands, hand)
+	for i := range s.Hands {
+		s.deal(i)
+		s.deal(i)
+	}
 
-	s.DealerCards = append(s.DealerCards, s.Deck.Draw(), s.Deck.Draw())
+	s.deal(DealerHand)
+	s.deal(DealerHand)
 
-	// s.PlayerCards = append(s.PlayerCards, s.Deck.Draw(), s.Deck.Draw())
-	// s.DealerCards = append(s.DealerCards, s.Deck.Draw(), s.Deck.Draw())
+	// с блэкджеком рука больше не играет
+	for _, hand := range s.Hands {
+		if hand.IsBlackjack() {
+			hand.IsStand = true
+		}
+	}
+	if s.Hands[0].IsStand {
+		s.NextHand()
+	}
 
 	return s
 }
 
+// deal сдает карту в руку (или дилеру) и записывает событие
+func (s *State) deal(index int) string {
+	card := s.Deck.Draw()
+	if index == DealerHand {
+		s.DealerCards = append(s.DealerCards, card)
+	} else {
+		s.Hands[index].Cards = append(s.Hands[index].Cards, card)
+	}
+	s.record(Event{Type: EventDeal, Hand: index, Card: card})
+	return card
+}
+
 // текущая рука
 func (s *State) Current() *Hand {
 	if s.CurrentHand >= len(s.Hands) {
@@ -97,6 +210,17 @@ func (s *State) Current() *Hand {
 	return s.Hands[s.CurrentHand]
 }
 
+// количество рук в боксе (растет после сплитов)
+func (s *State) BoxHands(box int) int {
+	n := 0
+	for _, h := range s.Hands {
+		if h.Box == box {
+			n++
+		}
+	}
+	return n
+}
+
 // Общая ставка всех рук
 func (s *State) TotalBet() int {
 	total := 0
@@ -106,20 +230,25 @@ func (s *State) TotalBet() int {
 	return total
 }
 
-// hit для текущей руки
+// hit для текущей руки; закрытая рука (перебор, Charlie) больше не берет
 func (s *State) Hit() string {
 	hand := s.Current()
-	if hand == nil {
+	if hand == nil || hand.IsDouble || hand.IsStand {
 		return ""
 	}
 
 	card := s.Deck.Draw()
 	hand.Cards = append(hand.Cards, card)
+	s.record(Event{Type: EventHit, Hand: s.CurrentHand, Card: card})
 
 	if hand.Score() > 21 {
 		hand.IsBust = true
 		hand.IsStand = true
 	}
+	// Charlie больше не играет: рука уже выиграла
+	if s.charlie(hand) {
+		hand.IsStand = true
+	}
 	return card
 }
 
@@ -128,26 +257,56 @@ func (s *State) Stand() {
 	hand := s.Current()
 	if hand != nil {
 		hand.IsStand = true
+		s.record(Event{Type: EventStand, Hand: s.CurrentHand})
+	}
+}
+
+// surrender — сдаться: рука закрыта, возвращается половина ставки
+func (s *State) Surrender() {
+	hand := s.Current()
+	if hand == nil {
+		return
+	}
+
+	hand.IsSurrender = true
+	hand.IsStand = true
+	s.record(Event{Type: EventSurrender, Hand: s.CurrentHand})
+}
+
+// forfeit — рука проиграна без сравнения с дилером (например, по таймауту)
+func (s *State) Forfeit() {
+	hand := s.Current()
+	if hand == nil {
+		return
 	}
+
+	hand.IsForfeit = true
+	hand.IsStand = true
+	s.record(Event{Type: EventForfeit, Hand: s.CurrentHand})
 }
 
-// double для текущей руки
+// double для текущей руки. В испанском варианте рука после удвоения
+// остается открытой, пока игрок не остановится или не спасет ставку.
 func (s *State) Double() string {
 	hand := s.Current()
 	if hand == nil || !hand.CanDouble() {
 		return ""
 	}
 
+	if s.freeDouble(hand) {
+		hand.Free += hand.Bet
+	}
 	hand.Bet *= 2
 	hand.IsDouble = true
 
 	card := s.Deck.Draw()
 	hand.Cards = append(hand.Cards, card)
+	s.record(Event{Type: EventDouble, Hand: s.CurrentHand, Card: card})
 
 	if hand.Score() > 21 {
 		hand.IsBust = true
 	}
-	hand.IsStand = true
+	hand.IsStand = !s.Spanish() || hand.IsBust || hand.Score() == 21
 
 	return card
 }
@@ -155,13 +314,14 @@ func (s *State) Double() string {
 // split
 func (s *State) Split() bool {
 	hand := s.Current()
-	if hand == nil || !hand.CanSplit() {
+	if !s.CanSplit() {
 		return false
 	}
 
 	// вторая карта
 	secondCard := hand.Cards[1]
-	isAces := hand.Cards[0] == "A"
+	isAces := Rank(hand.Cards[0]) == "A"
+	free := s.freeSplit(hand)
 
 	// первая карта в текущей руке
 	hand.Cards = []string{hand.Cards[0]}
@@ -170,15 +330,20 @@ func (s *State) Split() bool {
 
 	// новая рука для второй карты
 	newHand := NewHand(hand.Bet)
+	newHand.Box = hand.Box
 	newHand.Cards = []string{secondCard}
 	newHand.FromSplit = true
-	hand.SplitAces = isAces
-
-	//добираем по карте в каждую руку
-	hand.Cards = append(hand.Cards, s.Deck.Draw())
-	newHand.Cards = append(newHand.Cards, s.Deck.Draw())
+	newHand.SplitAces = isAces
+	if free {
+		newHand.Free = newHand.Bet
+	}
 
 	s.Hands = append(s.Hands[:s.CurrentHand+1], append([]*Hand{newHand}, s.Hands[s.CurrentHand+1:]...)...)
+	s.record(Event{Type: EventSplit, Hand: s.CurrentHand})
+
+	//добираем по карте в каждую руку
+	s.deal(s.CurrentHand)
+	s.deal(s.CurrentHand + 1)
 
 	if isAces {
 		hand.IsStand = true
@@ -209,19 +374,22 @@ func (s *State) AllHandsComplete() bool {
 }
 
 func (s *State) DealerPlay() {
-	allBust := true
+	// дилер не добирает, если ни одна рука с ним не сравнивается
+	settled := true
 	for _, h := range s.Hands {
-		if !h.IsBust {
-			allBust = false
+		if h.needsDealer() {
+			settled = false
 			break
 		}
 	}
-	if allBust {
+	if settled {
 		return
 	}
 
 	for CalculateScore(s.DealerCards) < 17 {
-		s.DealerCards = append(s.DealerCards, s.Deck.Draw())
+		card := s.Deck.Draw()
+		s.DealerCards = append(s.DealerCards, card)
+		s.record(Event{Type: EventDealerDraw, Hand: DealerHand, Card: card})
 	}
 }
 
@@ -229,28 +397,82 @@ func (s *State) DealerScore() int {
 	return CalculateScore(s.DealerCards)
 }
 
+// BlackjackPayout — выплата за блэкджек вместе со ставкой по правилам варианта
+func (s *State) BlackjackPayout() float64 {
+	if s.Switch() {
+		return SwitchBlackjackPays
+	}
+	return s.BlackjackPays
+}
+
 func (s *State) HandResult(hand *Hand) (Result, int) {
-	if hand.IsBust {
-		return ResultDealerWin, 0
+	result, payout, _ := s.handResult(hand)
+	return result, payout
+}
+
+// handResult — итог руки, выплата и правило стола, которое его решило
+func (s *State) handResult(hand *Hand) (Result, int, Reason) {
+	if hand.IsBust || hand.IsForfeit {
+		return ResultDealerWin, 0, ReasonNone
+	}
+	if hand.IsSurrender {
+		return ResultSurrender, hand.Real() / 2, ReasonNone
+	}
+
+	dealerBJ := IsBlackjack(s.DealerCards)
+	if hand.IsBlackjack() {
+		// в испанском варианте 21 игрока выигрывает всегда
+		if dealerBJ && !s.Spanish() {
+			return ResultPush, hand.Real(), ReasonNone
+		}
+		return ResultBlackjack, int(float64(hand.Bet) * s.BlackjackPayout()), ReasonNone
+	}
+	if dealerBJ {
+		return ResultDealerWin, 0, ReasonNone
 	}
 
 	dealerScore := s.DealerScore()
 	playerScore := hand.Score()
 
+	if s.Spanish() && playerScore == 21 {
+		if bonus := spanishBonus(hand); bonus > 0 {
+			return ResultBonus, hand.Bet + int(float64(hand.Bet)*bonus), ReasonNone
+		}
+		return ResultPlayerWin, hand.Bet * 2, ReasonNone
+	}
+
+	// Charlie выигрывает независимо от руки дилера
+	if s.charlie(hand) {
+		return ResultPlayerWin, hand.Bet + hand.Real(), ReasonCharlie
+	}
+
+	if s.dealer22Push() {
+		return ResultPush, hand.Real(), ReasonNone
+	}
+
+	// бесплатные фишки приносят только выигрыш
 	if dealerScore > 21 {
-		return ResultPlayerWin, hand.Bet * 2
+		return ResultPlayerWin, hand.Bet + hand.Real(), ReasonNone
 	}
 
 	if playerScore > dealerScore {
-		return ResultPlayerWin, hand.Bet * 2
+		return ResultPlayerWin, hand.Bet + hand.Real(), ReasonNone
 	} else if playerScore < dealerScore {
-		return ResultDealerWin, 0
+		return ResultDealerWin, 0, ReasonNone
+	}
+
+	switch {
+	case s.Rules.Player21Wins && playerScore == 21:
+		return ResultPlayerWin, hand.Bet + hand.Real(), ReasonPlayer21Wins
+	case s.Rules.DealerWinsTies:
+		return ResultDealerWin, 0, ReasonDealerTies
 	}
-	return ResultPush, hand.Bet
+	return ResultPush, hand.Real(), ReasonNone
 }
 
 func (s *State) Finish() {
 	s.IsActive = false
+	s.record(Event{Type: EventFinish, Hand: DealerHand})
 	s.DealerPlay()
 }
 
@@ -270,7 +492,7 @@ func (s *State) PlayerScore() int {
 
 func (s *State) CanSplit() bool {
 	hand := s.Current()
-	return hand != nil && hand.CanSplit()
+	return hand != nil && hand.CanSplit() && s.BoxHands(hand.Box) < MaxSplitHands
 }
 
 func (s *State) CanDouble() bool {
@@ -282,6 +504,10 @@ func (s *State) HasMultipleHands() bool {
 	return len(s.Hands) > 1
 }
 
+func (s *State) HasMultipleBoxes() bool {
+	return len(s.InitialBets) > 1
+}
+
 // func (s *State) Finish() Result {
 // 	s.IsActive = false
 // 	s.DealerPlay()
@@ -332,3 +558,32 @@ func (m *Manager) Delete(chatID int64) {
 	defer m.mu.Unlock()
 	delete(m.games, chatID)
 }
+
+// All возвращает снимок всех игр
+func (m *Manager) All() map[int64]*State {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	all := make(map[int64]*State, len(m.games))
+	for chatID, s := range m.games {
+		all[chatID] = s
+	}
+	return all
+}
+
+// Idle возвращает игры, в которых не было хода дольше d. LastAction читается
+// под блокировкой игры, а ее берут уже без блокировки менеджера: обработчики
+// под блокировкой игры сами вызывают Set и Delete.
+func (m *Manager) Idle(d time.Duration) map[int64]*State {
+	idle := make(map[int64]*State)
+	for chatID, s := range m.All() {
+		s.Lock()
+		last := s.LastAction
+		s.Unlock()
+
+		if time.Since(last) > d {
+			idle[chatID] = s
+		}
+	}
+	return idle
+}
@@ -0,0 +1,45 @@
+package game
+
+// Blackjack Switch: два бокса с равными ставками, до первого хода можно поменять
+// местами их вторые карты. Блэкджек платит 1:1, 22 у дилера — ничья.
+
+// SwitchBlackjackPays — выплата за блэкджек вместе со ставкой
+const SwitchBlackjackPays = 2.0
+
+func (s *State) Switch() bool {
+	return s.Variant == VariantSwitch
+}
+
+// CanSwitch — обмен еще возможен: два бокса и ни одного хода
+func (s *State) CanSwitch() bool {
+	if !s.Switch() || !s.IsActive || len(s.Hands) != 2 {
+		return false
+	}
+	for _, e := range s.Events {
+		if e.Type != EventBet && e.Type != EventDeal {
+			return false
+		}
+	}
+	return true
+}
+
+// SwitchCards меняет местами вторые карты боксов; блэкджек, собранный
+// обменом, тоже считается блэкджеком. Ход начинается с первой руки заново.
+func (s *State) SwitchCards() bool {
+	if !s.CanSwitch() {
+		return false
+	}
+
+	a, b := s.Hands[0], s.Hands[1]
+	a.Cards[1], b.Cards[1] = b.Cards[1], a.Cards[1]
+	s.record(Event{Type: EventSwitch, Hand: 0})
+
+	s.CurrentHand = 0
+	for _, hand := range s.Hands {
+		hand.IsStand = hand.IsBlackjack()
+	}
+	if s.Hands[0].IsStand {
+		s.NextHand()
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSwitchCards(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		bets    []int
+		cards   []string // первый бокс, второй бокс, дилер
+		hit     bool     // ход до обмена
+		ok      bool
+		want    [][]string
+		current int
+	}{
+		{
+			name:    "second cards swap",
+			variant: VariantSwitch,
+			bets:    []int{100, 100},
+			cards:   []string{"10♠", "6♥", "9♦", "10♣", "10♥", "7♦"},
+			ok:      true,
+			want:    [][]string{{"10♠", "10♣"}, {"9♦", "6♥"}},
+			current: 0,
+		},
+		{
+			name:    "blackjack made by the switch stands",
+			variant: VariantSwitch,
+			bets:    []int{100, 100},
+			cards:   []string{"A♠", "7♥", "9♦", "K♣", "10♥", "7♦"},
+			ok:      true,
+			want:    [][]string{{"A♠", "K♣"}, {"9♦", "7♥"}},
+			current: 1,
+		},
+		{
+			name:    "no switch after a move",
+			variant: VariantSwitch,
+			bets:    []int{100, 100},
+			cards:   []string{"2♠", "3♥", "9♦", "10♣", "10♥", "7♦", "4♠"},
+			hit:     true,
+			ok:      false,
+			want:    [][]string{{"2♠", "3♥", "4♠"}, {"9♦", "10♣"}},
+			current: 0,
+		},
+		{
+			name:    "no switch in classic",
+			variant: VariantClassic,
+			bets:    []int{100, 100},
+			cards:   []string{"10♠", "6♥", "9♦", "10♣", "10♥", "7♦"},
+			ok:      false,
+			want:    [][]string{{"10♠", "6♥"}, {"9♦", "10♣"}},
+			current: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newState(tt.bets, tt.variant, Rules{}, NewStackedDeck(tt.cards...))
+			if tt.hit {
+				s.Hit()
+			}
+
+			if got := s.SwitchCards(); got != tt.ok {
+				t.Fatalf("SwitchCards() = %v, want %v", got, tt.ok)
+			}
+			if got := handCards(s); !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("hands %v, want %v", got, tt.want)
+			}
+			if s.CurrentHand != tt.current {
+				t.Errorf("current hand %d, want %d", s.CurrentHand, tt.current)
+			}
+			if s.CanSwitch() {
+				t.Error("CanSwitch() after the switch window")
+			}
+		})
+	}
+}
+
+func TestSwitchResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		cards  []string
+		dealer []string
+		result Result
+		payout int
+	}{
+		{name: "blackjack pays even money", cards: []string{"A♠", "K♣"}, dealer: []string{"10♥", "7♦"}, result: ResultBlackjack, payout: 200},
+		{name: "dealer 22 pushes", cards: []string{"10♠", "9♥"}, dealer: []string{"10♥", "6♦", "6♣"}, result: ResultPush, payout: 100},
+		{name: "win pays double", cards: []string{"10♠", "9♥"}, dealer: []string{"10♥", "7♦"}, result: ResultPlayerWin, payout: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := &Hand{Cards: tt.cards, Bet: 100, IsStand: true}
+			s := &State{Variant: VariantSwitch, DealerCards: tt.dealer, Hands: []*Hand{hand}, BlackjackPays: 2.5}
+			result, payout := s.HandResult(hand)
+			if result != tt.result || payout != tt.payout {
+				t.Errorf("HandResult() = %s, %d, want %s, %d", result.Name(), payout, tt.result.Name(), tt.payout)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package game
+
+import (
+	"math/rand"
+	"slices"
+)
+
+// Variant — правила игры; пустой — классический блэкджек
+type Variant string
+
+const (
+	VariantClassic Variant = ""
+	VariantSpanish Variant = "spanish"
+	VariantFreeBet Variant = "freebet"
+	VariantSwitch  Variant = "switch"
+)
+
+// Variants — варианты в порядке показа
+var Variants = []Variant{VariantClassic, VariantSpanish, VariantFreeBet, VariantSwitch}
+
+// Name — имя варианта в командах: /play spanish 100
+func (v Variant) Name() string {
+	if v == VariantClassic {
+		return "classic"
+	}
+	return string(v)
+}
+
+// ParseVariant находит вариант по имени из команды
+func ParseVariant(name string) (Variant, bool) {
+	for _, v := range Variants {
+		if v.Name() == name {
+			return v, true
+		}
+	}
+	return VariantClassic, false
+}
+
+// spanishNames — ранги испанской колоды: 48 карт, десяток нет (картинки остаются)
+var spanishNames = slices.DeleteFunc(slices.Clone(cardNames), func(name string) bool {
+	return name == "10"
+})
+
+// NewVariantDeck создает колоду варианта; src задает тасование (nil — CSPRNG)
+func NewVariantDeck(variant Variant, src rand.Source) *Deck {
+	if variant == VariantSpanish {
+		return NewSpanishDeck(src)
+	}
+	return NewDeck(src)
+}
+
+// NewVariantState раздает игру по правилам варианта; src задает тасование (nil — CSPRNG)
+func NewVariantState(bets []int, variant Variant, src rand.Source) *State {
+	return NewRulesState(bets, variant, Rules{}, src)
+}
+
+// NewRulesState раздает игру варианта с дополнительными правилами стола
+func NewRulesState(bets []int, variant Variant, rules Rules, src rand.Source) *State {
+	return newState(bets, variant, rules, NewVariantDeck(variant, src))
+}
+
+// dealer22Push — 22 у дилера не проигрыш, а ничья (Free Bet и Switch)
+func (s *State) dealer22Push() bool {
+	return (s.FreeBet() || s.Switch()) && s.DealerScore() == 22
+}
+
+func (s *State) Spanish() bool {
+	return s.Variant == VariantSpanish
+}
+
+// CanSurrender — поздняя сдача в испанском варианте: на двух первых картах
+// до сплита или после удвоения (спасение: возвращается удвоение, ставка проиграна)
+func (s *State) CanSurrender() bool {
+	hand := s.Current()
+	if !s.Spanish() || hand == nil || hand.IsStand {
+		return false
+	}
+	return hand.IsDouble || (len(hand.Cards) == 2 && !hand.FromSplit)
+}
+
+// spanishBonus — выплата «к одному» за 21 в испанском варианте: 6-7-8 и 7-7-7
+// разной масти 3:2, одной масти 2:1, пиками 3:1; 21 из пяти карт 3:2, из шести 2:1,
+// из семи и больше 3:1. После удвоения бонуса нет, 0 — обычный выигрыш.
+func spanishBonus(hand *Hand) float64 {
+	if hand.IsDouble || hand.Score() != 21 {
+		return 0
+	}
+
+	n := len(hand.Cards)
+	if n == 3 {
+		ranks := make([]string, n)
+		for i, card := range hand.Cards {
+			ranks[i] = Rank(card)
+		}
+		slices.Sort(ranks)
+		if !slices.Equal(ranks, []string{"6", "7", "8"}) && !slices.Equal(ranks, []string{"7", "7", "7"}) {
+			return 0
+		}
+
+		suit := Suit(hand.Cards[0])
+		for _, card := range hand.Cards[1:] {
+			if Suit(card) != suit {
+				suit = ""
+			}
+		}
+		switch suit {
+		case "":
+			return 1.5
+		case "♠":
+			return 3
+		}
+		return 2
+	}
+
+	switch {
+	case n >= 7:
+		return 3
+	case n == 6:
+		return 2
+	case n == 5:
+		return 1.5
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package game
+
+import "testing"
+
+func TestSpanishBonus(t *testing.T) {
+	tests := []struct {
+		name   string
+		cards  []string
+		double bool
+		want   float64
+	}{
+		{name: "two card 21", cards: []string{"A♠", "K♥"}, want: 0},
+		{name: "three card 21", cards: []string{"9♠", "5♥", "7♦"}, want: 0},
+		{name: "four card 21", cards: []string{"2♠", "3♥", "6♦", "Q♣"}, want: 0},
+		{name: "five card 21", cards: []string{"2♠", "3♥", "4♦", "5♣", "7♠"}, want: 1.5},
+		{name: "six card 21", cards: []string{"A♠", "2♥", "3♦", "4♣", "5♠", "6♥"}, want: 2},
+		{name: "seven card 21", cards: []string{"A♠", "A♥", "2♦", "3♣", "4♠", "5♥", "5♦"}, want: 3},
+		{name: "eight card 21", cards: []string{"A♠", "A♥", "A♦", "A♣", "2♠", "3♥", "4♦", "8♣"}, want: 3},
+		{name: "five cards short of 21", cards: []string{"2♠", "3♥", "4♦", "5♣", "6♠"}, want: 0},
+		{name: "6-7-8 mixed suits", cards: []string{"6♠", "7♥", "8♦"}, want: 1.5},
+		{name: "6-7-8 suited", cards: []string{"8♥", "6♥", "7♥"}, want: 2},
+		{name: "6-7-8 spades", cards: []string{"7♠", "8♠", "6♠"}, want: 3},
+		{name: "7-7-7 mixed suits", cards: []string{"7♠", "7♥", "7♣"}, want: 1.5},
+		{name: "7-7-7 spades", cards: []string{"7♠", "7♠", "7♠"}, want: 3},
+		{name: "doubled 6-7-8", cards: []string{"6♠", "7♠", "8♠"}, double: true, want: 0},
+		{name: "doubled five cards", cards: []string{"2♠", "3♥", "4♦", "5♣", "7♠"}, double: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := &Hand{Cards: tt.cards, Bet: 100, IsDouble: tt.double}
+			if got := spanishBonus(hand); got != tt.want {
+				t.Errorf("spanishBonus(%v) = %v, want %v", tt.cards, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpanishResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		cards  []string
+		double bool
+		dealer []string
+		result Result
+		payout int
+	}{
+		{name: "bonus 21", cards: []string{"2♠", "3♥", "4♦", "5♣", "7♠"}, dealer: []string{"K♠", "Q♥", "A♦"}, result: ResultBonus, payout: 250},
+		{name: "doubled 21 is a plain win", cards: []string{"6♠", "7♠", "8♠"}, double: true, dealer: []string{"K♠", "Q♥", "A♦"}, result: ResultPlayerWin, payout: 200},
+		{name: "player 21 beats dealer 21", cards: []string{"9♠", "5♥", "7♦"}, dealer: []string{"K♠", "5♥", "6♦"}, result: ResultPlayerWin, payout: 200},
+		{name: "player blackjack beats dealer blackjack", cards: []string{"A♠", "K♥"}, dealer: []string{"A♥", "Q♦"}, result: ResultBlackjack, payout: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := &Hand{Cards: tt.cards, Bet: 100, IsDouble: tt.double, IsStand: true}
+			s := &State{Variant: VariantSpanish, DealerCards: tt.dealer, Hands: []*Hand{hand}, BlackjackPays: 2.5}
+			result, payout := s.HandResult(hand)
+			if result != tt.result || payout != tt.payout {
+				t.Errorf("HandResult() = %v, %d, want %v, %d", result.Name(), payout, tt.result.Name(), tt.payout)
+			}
+		})
+	}
+}
+
+func TestCanSurrender(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		hand    Hand
+		want    bool
+	}{
+		{name: "spanish first two cards", variant: VariantSpanish, hand: Hand{Cards: []string{"K♠", "6♥"}}, want: true},
+		{name: "spanish rescue after double", variant: VariantSpanish, hand: Hand{Cards: []string{"5♠", "6♥", "4♦"}, IsDouble: true}, want: true},
+		{name: "spanish after split", variant: VariantSpanish, hand: Hand{Cards: []string{"8♠", "8♥"}, FromSplit: true}, want: false},
+		{name: "spanish after hit", variant: VariantSpanish, hand: Hand{Cards: []string{"5♠", "6♥", "4♦"}}, want: false},
+		{name: "spanish closed hand", variant: VariantSpanish, hand: Hand{Cards: []string{"K♠", "6♥"}, IsStand: true}, want: false},
+		{name: "classic", variant: VariantClassic, hand: Hand{Cards: []string{"K♠", "6♥"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &State{Variant: tt.variant, Hands: []*Hand{&tt.hand}}
+			if got := s.CanSurrender(); got != tt.want {
+				t.Errorf("CanSurrender() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
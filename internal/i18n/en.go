@@ -0,0 +1,382 @@
+package i18n
+
+var enPlurals = map[string][]string{
+	"chips":   {"chip", "chips"},
+	"games":   {"game", "games"},
+	"hands":   {"hand", "hands"},
+	"players": {"player", "players"},
+	"days":    {"day", "days"},
+	"boxes":   {"box", "boxes"},
+	"rounds":  {"round", "rounds"},
+}
+
+var en = map[string]string{
+	// Common
+	"error":         "❌ Something went wrong",
+	"error_later":   "❌ Something went wrong. Please try again later.",
+	"game_inactive": "No active game",
+	"balance_toast": "💵 %d",
+
+	// Buttons
+	"btn_hit":         "👊 Hit",
+	"btn_stand":       "✋ Stand",
+	"btn_double":      "💰 Double",
+	"btn_split":       "✂️ Split",
+	"btn_surrender":   "🏳️ Surrender",
+	"btn_rescue":      "🛟 Rescue",
+	"btn_double_free": "🎁 Free double",
+	"btn_split_free":  "🎁 Free split",
+	"btn_switch":      "🔀 Switch second cards",
+	"btn_again":       "🔄 Again (%s)",
+	"btn_balance":     "💵 Balance",
+	"btn_table_bet":   "🪑 Bet %d",
+	"btn_table_leave": "🚪 Leave",
+
+	"btn_tournament_next": "🏆 Next round (%s)",
+	"btn_side":            ", 21+3: %d",
+	"btn_pairs":           ", PP: %d",
+
+	"btn_chips":         "🪙 Chips",
+	"btn_chip":          "+%d",
+	"btn_slip_clear":    "✖️ Clear",
+	"btn_slip_half":     "½",
+	"btn_slip_max":      "Max",
+	"btn_slip_repeat":   "🔁 Last %d",
+	"btn_slip_double":   "×2 last: %d",
+	"btn_slip_favorite": "⭐ %d",
+	"btn_slip_deal":     "🃏 Deal %d",
+	"btn_slip_save":     "⭐ Save",
+	"btn_slip_unsave":   "☆ Forget",
+
+	"slip":             "🪙 Bet: %d\nLimits: %d–%d · balance: %d",
+	"slip_placed":      "🪙 Bet %d placed",
+	"slip_fav_added":   "⭐ %d saved to favourites",
+	"slip_fav_removed": "☆ %d removed from favourites",
+
+	// Commands
+	"start": "🎰 Welcome to Blackjack!\n\n" +
+		"💵 Balance: %s\n\n" +
+		"/play <bet> — play\n" +
+		"/bet — build a bet from chips\n" +
+		"/play 100 100 50 — several boxes\n" +
+		"/play 100 side=10 — with a 21+3 side bet\n" +
+		"/play 100 pairs=10 — with a Perfect Pairs side bet\n" +
+		"/play spanish 100 — Spanish 21\n" +
+		"/play freebet 100 — Free Bet Blackjack\n" +
+		"/play switch 100 — Blackjack Switch, two boxes of 100\n" +
+		"/balance — statistics\n" +
+		"/bonus — daily bonus\n" +
+		"/achievements — achievements\n" +
+		"/tournament — tournaments\n" +
+		"/top — leaderboard\n" +
+		"/seed — your seed for fair dealing\n" +
+		"/verify <round> — verify a round\n" +
+		"/replay <round> — replay a round\n" +
+		"/lang — language\n" +
+		"/images — cards as a picture\n" +
+		"/token — HTTP API token\n" +
+		"/live — live view link\n" +
+		"/help — rules",
+	"help": "📖 Blackjack rules:\n\n" +
+		"🎯 Goal: get 21 or beat the dealer\n\n" +
+		"📊 Points: 2-10 face value, J/Q/K = 10, A = 11 or 1\n\n" +
+		"🎮 Actions:\n" +
+		"• Hit — take a card\n" +
+		"• Stand — stop\n" +
+		"• Double — double the bet and take one card\n" +
+		"• Split — split a pair\n\n" +
+		"✂️ Split: two cards of equal value can be split into two hands. Each hand plays separately.\n\n" +
+		"📦 Boxes: /play 100 100 50 — up to %d boxes with their own bets, played in turn.\n\n" +
+		"🎰 Blackjack pays x%.1f\n\n" +
+		"%s" +
+		"%s" +
+		"🔐 Fair dealing: before each round the bot publishes a SHA-256 hash of the server seed. " +
+		"The deck is shuffled from the server seed, your seed (/seed) and the round number. " +
+		"After the round the seed is revealed and can be checked with /verify.",
+	"help_side": "🎲 21+3: /play 100 side=10 — a side bet on each box. Your first two cards and the dealer's upcard " +
+		"make a poker hand, paid right after the deal:\n%s\n",
+	"help_pairs": "👯 Perfect Pairs: /play 100 pairs=10 — a side bet that your first two cards are a pair:\n%s\n",
+	"help_spanish": "🇪🇸 Spanish 21: /play spanish 100 — 48-card deck without tens. " +
+		"Player 21 always wins; 21 pays 3:2 with 5 cards, 2:1 with 6 and 3:1 with 7 or more, " +
+		"6-7-8 and 7-7-7 pay 3:2, suited 2:1, in spades 3:1 (no bonuses after doubling). " +
+		"You may surrender on the first two cards, double after a split and, after doubling, " +
+		"rescue the hand: the double is returned, the original bet is lost.\n\n",
+	"help_freebet": "🎁 Free Bet: /play freebet 100 — doubles on hard 9-11 and splits of any pair except tens are free. " +
+		"Free chips pay only the winnings, without the stake. A dealer 22 pushes all hands still in play.\n\n",
+	"help_switch": "🔀 Blackjack Switch: /play switch 100 — two boxes with equal bets. Before your first move " +
+		"you may swap the second cards between them. Blackjack pays 1:1, a dealer 22 pushes all hands still in play.\n\n",
+	"help_side_line": "• %s — %d:1\n",
+	"help_timeout":   "⏰ You have %s per move, then the hand is closed automatically.\n\n",
+	"balance": "💰 Balance: %s\n\n" +
+		"📊 Statistics:\n" +
+		"🎮 Played: %s\n" +
+		"✅ Wins: %d (%.1f%%)\n" +
+		"❌ Losses: %d\n" +
+		"🤝 Draws: %d",
+	"top_empty": "🏆 Nobody has played yet!",
+	"top_title": "🏆 Leaderboard:\n\n",
+	"top_line":  "%s %d 💰 | %s (%.0f%%)\n",
+
+	"bonus":            "🎁 Daily bonus: +%d\n🔥 Streak: %s\n💵 Balance: %d",
+	"bonus_claimed":    "⏳ Today's bonus is already claimed. The next one is in %s",
+	"bailout":          "🆘 You ran out of chips, your balance was topped up to %d. The next top-up is possible in %s",
+	"no_funds_bailout": "❌ Insufficient funds! Balance: %d\n🆘 Free top-up in %s, /bonus — daily bonus",
+
+	"lang_choose":  "🌐 Choose your language",
+	"lang_set":     "✅ Language: English",
+	"lang_unknown": "❌ Available languages: ru, en",
+	"lang_name":    "🇬🇧 English",
+
+	"images_on":    "🖼 Games are now shown as a picture of the table. /images off — back to text",
+	"images_off":   "📝 Games are now shown as text. /images on — picture of the table",
+	"images_usage": "❌ Usage: /images, /images on or /images off",
+
+	"token_issued":       "🔑 HTTP API token:\n%s\n\nSend it in the Authorization: Bearer <token> header. Your previous token no longer works.",
+	"token_private_only": "🔑 Tokens are only issued in a private chat with the bot",
+
+	"live_link":     "📺 Live view of this chat's rounds (WebSocket, JSON):\n%s",
+	"live_disabled": "📺 Live view is not enabled on this bot",
+
+	"banned": "🚫 You are banned from the game",
+
+	// Admin
+	"admin_usage": "🛠 Admin commands:\n\n" +
+		"/admin grant <user> <amount> — add chips (negative takes them away)\n" +
+		"/admin set <user> <amount> — set the balance\n" +
+		"/admin ban <user> — ban a player\n" +
+		"/admin unban <user> — unban a player\n" +
+		"/admin player <user> — player info\n" +
+		"/admin broadcast <text> — message all players\n" +
+		"/admin tournament <start> <rounds> <chips> <prizes> — schedule a tournament",
+	"admin_invalid_amount":    "❌ Invalid amount",
+	"admin_not_found":         "❌ Player %d not found",
+	"admin_negative":          "❌ Balance cannot be negative",
+	"admin_already":           "❌ Player %d is already in this state",
+	"admin_balance":           "✅ Player %d: %+d, balance %d",
+	"admin_banned":            "🚫 Player %d is banned",
+	"admin_unbanned":          "✅ Player %d is unbanned",
+	"admin_player":            "👤 Player %d\n💰 Balance: %d\n🎮 Games: %d (wins %d, losses %d, draws %d)\n🌐 Language: %s\n🚫 Banned: %s",
+	"admin_yes":               "yes",
+	"admin_no":                "no",
+	"admin_ledger_title":      "\n\n📒 Ledger:\n",
+	"admin_ledger_line":       "%s %s %+d → %d (admin %d)\n",
+	"admin_broadcast_empty":   "❌ Message text is missing",
+	"admin_broadcast_started": "📣 Sending to %s…",
+	"admin_broadcast_done":    "📣 Broadcast finished: %d sent, %d failed",
+	"admin_tournament_invalid": "❌ Example: /admin tournament 2h 10 1000 500,300,100\n" +
+		"Start: in how long (2h) or when in UTC (2026-10-20T18:00); prizes for places, comma-separated",
+	"admin_tournament_created": "🏆 Tournament #%d scheduled: start %s UTC, %s, %d chips, prizes %s",
+	"admin_tournament_exists":  "❌ Another tournament is already scheduled or running",
+
+	// Bets and rounds
+	"too_many_boxes":  "❌ At most %s per round",
+	"invalid_bet":     "❌ Invalid bet. Example: %s %d",
+	"bet_range":       "❌ Bet must be between %d and %d",
+	"no_funds":        "❌ Insufficient funds! Balance: %d",
+	"round_started":   "🔐 Round #%d · hash: %s\n💰 Bet: %s | Balance: %d\n\n%s",
+	"bust_next":       "💥 Bust on hand %d!\n\n%s",
+	"stand_next":      "✋ Standing. Moving to hand %d\n\n%s",
+	"double_no_funds": "❌ Insufficient funds to double",
+	"double_next":     "💰 Doubled! %s Moving to hand %d\n\n%s",
+	"double_rescue":   "💰 Doubled! Stand or rescue the hand: the double is returned, the bet is lost\n\n%s",
+	"surrender_next":  "🏳️ Surrendered. Moving to hand %d\n\n%s",
+	"switch_done":     "🔀 Second cards switched\n\n%s",
+	"switch_bets":     "❌ Blackjack Switch is played on two boxes with equal bets. Example: /play switch 100",
+	"variant_round":   "%s\n",
+	"split_no_funds":  "❌ Insufficient funds to split",
+	"split_aces":      "✂️ Split aces! One card to each hand.",
+	"split_done":      "✂️ Split! You now have %s.\n💰 Total bet: %d | Balance: %d\n\n%s",
+	"game_restored":   "♻️ The bot was restarted, your game continues\n\n%s",
+
+	// Side bets
+	"side_range": "❌ A side bet must be between %d and %d",
+	"side_box":   " · box %d",
+	"side_win":   "\n🎲 %s: %v — %s! +%d",
+	"side_loss":  "\n🎲 %s: %v — no hand",
+
+	"poker_flush":           "Flush",
+	"poker_straight":        "Straight",
+	"poker_three_of_a_kind": "Three of a kind",
+	"poker_straight_flush":  "Straight flush",
+	"poker_suited_trips":    "Suited trips",
+
+	"pair_mixed":      "Mixed pair",
+	"pair_coloured":   "Coloured pair",
+	"pair_perfect":    "Perfect pair",
+	"timeout_expired": "⏰ Time for your move is up\n\n",
+
+	// Achievements
+	"achievements_title":   "🏅 Achievements %d/%d:\n\n",
+	"achievement_done":     "✅ %s — %s (%s)\n",
+	"achievement_locked":   "🔒 %s — %s\n",
+	"achievement_unlocked": "🏅 Achievement unlocked: %s",
+	"table_achievement":    "🏅 %s unlocks an achievement: %s",
+	"balance_achievements": "\n🏅 Achievements: %d/%d",
+
+	"ach_first_win":             "First win",
+	"ach_first_win_desc":        "win a round",
+	"ach_first_blackjack":       "Natural",
+	"ach_first_blackjack_desc":  "get your first blackjack",
+	"ach_suited_blackjack":      "Suited blackjack",
+	"ach_suited_blackjack_desc": "blackjack with an ace and a ten-card of the same suit",
+	"ach_win_streak_5":          "On a roll",
+	"ach_win_streak_5_desc":     "win 5 rounds in a row",
+	"ach_double_eleven":         "Textbook double",
+	"ach_double_eleven_desc":    "double down on 11 and win",
+	"ach_split_four":            "Four-way split",
+	"ach_split_four_desc":       "split a box into four hands and come out ahead",
+
+	// Tournaments
+	"tournament_usage": "🏆 Tournaments:\n\n" +
+		"/tournament — current tournament and standings\n" +
+		"/tournament join — register\n" +
+		"/tournament play <bet> — play a tournament round (in a private chat)",
+	"tournament_none": "🏆 No tournaments are scheduled",
+	"tournament_scheduled": "🏆 Tournament #%d\n" +
+		"🕒 Start: %s UTC (in %s)\n" +
+		"🎲 %s, %d tournament chips each\n" +
+		"🎁 Prizes: %s\n" +
+		"👥 Registered: %s\n\n%s",
+	"tournament_join_hint":    "/tournament join — register",
+	"tournament_registered":   "✅ You are registered",
+	"tournament_joined":       "✅ %s is registered for tournament #%d. Start: %s UTC, %d tournament chips",
+	"tournament_closed":       "❌ Registration is closed",
+	"tournament_not_entered":  "❌ You are not registered for this tournament",
+	"tournament_not_running":  "❌ No tournament is running right now. /tournament — schedule",
+	"tournament_no_rounds":    "🏁 You have played all your tournament rounds",
+	"tournament_no_chips":     "❌ Not enough tournament chips for this bet",
+	"tournament_round_active": "❌ Finish the current round first",
+	"tournament_private_only": "🏆 Tournament rounds are played in a private chat with the bot",
+	"tournament_started": "🏁 Tournament #%d has started: %s, %d tournament chips each, until %s UTC.\n" +
+		"Your main balance is not affected. Play: /tournament play <bet>",
+	"tournament_round":        "🏆 Tournament #%d · round %d/%d\n",
+	"tournament_round_done":   "\n🏆 Tournament chips: %d · round %d/%d · place %d/%d",
+	"tournament_play_hint":    "\n\n/tournament play <bet> — play (rounds left: %d)",
+	"tournament_title":        "🏆 Tournament #%d · %s\n",
+	"tournament_until":        "⏳ Until %s UTC\n\n",
+	"tournament_finished":     "🏁 Finished\n\n",
+	"tournament_empty":        "Nobody registered",
+	"tournament_line":         "%s %s — %d · %d/%d%s\n",
+	"tournament_prize_suffix": " · 🎁 +%d",
+	"tournament_you":          " 👈",
+	"tournament_over":         "🏁 Tournament #%d is over! Your place: %d of %d",
+	"tournament_prize":        "\n🎁 Prize: %s added to your balance",
+
+	// Hands
+	"hand_box_hand": "🎴 Box %d, hand %d:",
+	"hand_box":      "🎴 Box %d:",
+	"hand_n":        "🎴 Hand %d:",
+	"hand_bet_free": " · %d + 🎁%d free",
+	"dealer_hidden": "🃏 Dealer: [%s, ?]",
+	"dealer":        "🃏 Dealer: %v (%d)",
+	"total_win":     "\n💰 Won: +%s",
+	"end_balance":   "\n💵 Balance: %d",
+
+	"result_blackjack": "🎰 BLACKJACK! x%.1f",
+	"result_win":       "🎉 Win!",
+	"result_loss":      "😔 Loss",
+	"reason_charlie":   " — %d-card Charlie",
+	"reason_21wins":    " — 21 beats dealer's 21",
+	"reason_ties":      " — dealer wins ties",
+	"rule_charlie":     "%d-card Charlie",
+	"rule_21wins":      "21 beats dealer's 21",
+	"rule_ties":        "dealer wins ties",
+	"result_push":      "🤝 Push",
+	"result_surrender": "🏳️ Surrender, %d returned",
+	"result_bonus":     "🎉 Bonus 21! +%d",
+
+	"variant_classic": "🃏 Classic",
+	"variant_spanish": "🇪🇸 Spanish 21",
+	"variant_freebet": "🎁 Free Bet",
+	"variant_switch":  "🔀 Blackjack Switch",
+
+	// Fair dealing
+	"reveal":             "\n\n🔓 Round #%d\nServer seed: %s\nVerify: /verify %d",
+	"reveal_next":        "\n🔐 Next round hash: %s",
+	"seed_too_long":      "❌ Seed must be at most 64 characters",
+	"seed_info":          "🌱 Your seed: %s\n🔐 Server seed hash for the next round: %s\n\nChange seed: /seed <text>",
+	"round_missing":      "❌ Specify a round number. Example: %s 42",
+	"round_invalid":      "❌ Invalid round number",
+	"round_not_found":    "❌ Round not found",
+	"round_not_finished": "⏳ The round is not finished yet, the server seed is still secret",
+	"verify_failed":      "❌ Round #%d failed verification: %v",
+	"verify_ok": "✅ Round #%d is fair\n\n" +
+		"🔐 Hash: %s\n" +
+		"🔓 Server seed: %s\n" +
+		"🌱 Client seed: %s\n" +
+		"🃏 Cards: %v",
+
+	// Replay
+	"replay_no_log":      "❌ Round #%d has no event log",
+	"replay_title":       "🎬 Replay of round #%d\n",
+	"replay_failed":      "❌ Round #%d could not be replayed: %v",
+	"replay_hand":        "hand",
+	"replay_hand_n":      "hand %d",
+	"replay_bet":         "💰 Bet %d",
+	"replay_deal":        "🎴 Deal: you %s, dealer %v",
+	"replay_card":        "🎴 %s: %s → %v (%d)",
+	"replay_hit":         "👊 Hit, %s: %s → %v (%d)",
+	"replay_stand":       "✋ Stand, %s",
+	"replay_double":      "💰 Double, %s: %s → %v (%d)",
+	"replay_split":       "✂️ Split",
+	"replay_switch":      "🔀 Switch: %v and %v",
+	"replay_surrender":   "🏳️ Surrender, %s",
+	"replay_forfeit":     "⏰ Hand closed on timeout, %s",
+	"replay_reveal":      "🃏 Dealer reveals: %v (%d)",
+	"replay_dealer_draw": "🃏 Dealer draws %s → %v (%d)",
+
+	// Group table
+	"table_help": "🎲 Blackjack table for the whole chat (up to %d seats)\n\n" +
+		"/join — take a seat\n" +
+		"/bet <amount> — place a bet (seats you automatically)\n" +
+		"/leave — leave the table\n" +
+		"/table — table status\n" +
+		"/table spanish, /table freebet — table rules (/table classic — back)\n" +
+		"/table rules charlie=5 21wins ties — house rules (/table rules off — none)\n" +
+		"/balance — your balance\n" +
+		"/bonus — daily bonus\n" +
+		"/achievements — achievements\n" +
+		"/tournament — tournaments\n" +
+		"/lang — language\n" +
+		"/live — live view link\n\n" +
+		"The first bet starts a countdown, then cards are dealt. Players act in turn, each with their own balance.",
+	"table_round":            "🔐 Round #%d · hash: %s",
+	"table_aborted":          "🛑 The bot is restarting, the round was cancelled and bets were returned",
+	"table_bailout":          "🆘 %s ran out of chips, balance topped up to %d",
+	"table_no_funds_bailout": "❌ %s: insufficient funds! Balance: %d\n🆘 Free top-up in %s",
+	"table_left":             "🚪 %s leaves the table\n\n%s",
+	"table_empty":            "🎲 The table is empty. /bet <amount> — sit down and bet",
+	"table_no_funds":         "❌ %s: insufficient funds! Balance: %d",
+	"table_refunded":         "❌ Something went wrong, bets were returned",
+	"table_turn_of":          "It's %s's turn",
+	"table_bust":             "💥 %s: bust!",
+	"table_stand":            "✋ %s stands",
+	"table_double":           "💰 %s doubles",
+	"table_split":            "✂️ %s splits",
+	"table_surrender":        "🏳️ %s surrenders",
+	"table_double_no_funds":  "❌ %s: insufficient funds to double",
+	"table_split_no_funds":   "❌ %s: insufficient funds to split",
+	"table_now":              "\n\n👉 %s to act",
+	"table_round_over":       "🏁 Round over\n\n",
+	"table_player_balance":   "   💵 %s: %d",
+	"table_lobby":            "🎲 Table: %d/%d\n\n",
+	"table_lobby_variant":    "Rules: %s\n",
+	"table_lobby_rules":      "House rules: %s\n",
+	"table_rules":            "🏠 House rules: %s",
+	"table_rules_off":        "🏠 House rules are off",
+	"table_rules_usage":      "❌ House rules: /table rules charlie=N (N from %d to %d), 21wins, ties — any combination; /table rules off — none",
+	"table_variant":          "🎲 Table rules: %s",
+	"table_variant_usage":    "❌ Rules: /table classic, /table spanish or /table freebet",
+	"table_seat_bet":         "%d. %s — 💰 %d\n",
+	"table_seat_wait":        "%d. %s — waiting for a bet\n",
+	"table_countdown":        "\n⏳ Dealing in %s",
+	"table_bet_hint":         "\n/bet <amount> — place a bet",
+	"table_hand_n":           "%s (hand %d)",
+	"table_timeout":          "⏰ %s: time is up",
+	"table_full":             "❌ The table is full (%d seats)",
+	"table_already_seated":   "❌ You are already at the table",
+	"table_not_seated":       "❌ You are not at the table. /join — take a seat",
+	"table_wrong_phase":      "⏳ A round is in progress, please wait for it to end",
+	"table_already_bet":      "❌ Bet already placed",
+	"table_bets_placed":      "❌ Bets are already on the table, change the rules after the round",
+}
@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+
+	Default = Russian
+)
+
+// Languages — доступные языки в порядке показа в /lang
+var Languages = []Lang{Russian, English}
+
+var catalogs = map[Lang]map[string]string{
+	Russian: ru,
+	English: en,
+}
+
+var plurals = map[Lang]map[string][]string{
+	Russian: ruPlurals,
+	English: enPlurals,
+}
+
+// Parse проверяет, что язык поддерживается
+func Parse(s string) (Lang, bool) {
+	lang := Lang(strings.ToLower(s))
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// Match выбирает язык по User.LanguageCode из Telegram (например, "en-US");
+// русскоязычным соседям достается русский, остальным — английский
+func Match(code string) Lang {
+	base, _, _ := strings.Cut(strings.ToLower(code), "-")
+	switch base {
+	case "":
+		return Default
+	case "ru", "uk", "be", "kk":
+		return Russian
+	}
+	if lang, ok := Parse(base); ok {
+		return lang
+	}
+	return English
+}
+
+// Printer форматирует сообщения на одном языке
+type Printer struct {
+	lang Lang
+}
+
+func New(lang Lang) *Printer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = Default
+	}
+	return &Printer{lang: lang}
+}
+
+func (p *Printer) Lang() Lang {
+	return p.lang
+}
+
+// T возвращает сообщение по ключу; при отсутствии перевода берется язык по умолчанию
+func (p *Printer) T(key string, args ...any) string {
+	msg, ok := catalogs[p.lang][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			return key
+		}
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N возвращает число с согласованным словом: "1 игра", "3 игры", "5 игр"
+func (p *Printer) N(key string, n int) string {
+	forms, ok := plurals[p.lang][key]
+	if !ok {
+		forms, ok = plurals[Default][key]
+		if !ok {
+			return fmt.Sprintf("%d %s", n, key)
+		}
+	}
+
+	var i int
+	if p.lang == Russian {
+		i = pluralRU(n)
+	} else {
+		i = pluralEN(n)
+	}
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return fmt.Sprintf("%d %s", n, forms[i])
+}
+
+// один / несколько (2–4) / много
+func pluralRU(n int) int {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	}
+	return 2
+}
+
+func pluralEN(n int) int {
+	if n == 1 || n == -1 {
+		return 0
+	}
+	return 1
+}
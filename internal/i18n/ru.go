@@ -0,0 +1,382 @@
+package i18n
+
+var ruPlurals = map[string][]string{
+	"chips":   {"фишка", "фишки", "фишек"},
+	"games":   {"игра", "игры", "игр"},
+	"hands":   {"рука", "руки", "рук"},
+	"players": {"игрок", "игрока", "игроков"},
+	"days":    {"день", "дня", "дней"},
+	"boxes":   {"бокс", "бокса", "боксов"},
+	"rounds":  {"раунд", "раунда", "раундов"},
+}
+
+var ru = map[string]string{
+	// Общее
+	"error":         "❌ Ошибка",
+	"error_later":   "❌ Ошибка. Попробуйте позже.",
+	"game_inactive": "Игра не активна",
+	"balance_toast": "💵 %d",
+
+	// Кнопки
+	"btn_hit":         "👊 Ещё",
+	"btn_stand":       "✋ Хватит",
+	"btn_double":      "💰 Удвоить",
+	"btn_split":       "✂️ Разделить",
+	"btn_surrender":   "🏳️ Сдаться",
+	"btn_rescue":      "🛟 Спасти",
+	"btn_double_free": "🎁 Удвоить бесплатно",
+	"btn_split_free":  "🎁 Разделить бесплатно",
+	"btn_switch":      "🔀 Поменять вторые карты",
+	"btn_again":       "🔄 Ещё (%s)",
+	"btn_balance":     "💵 Баланс",
+	"btn_table_bet":   "🪑 Ставка %d",
+	"btn_table_leave": "🚪 Встать",
+
+	"btn_tournament_next": "🏆 Следующий раунд (%s)",
+	"btn_side":            ", 21+3: %d",
+	"btn_pairs":           ", PP: %d",
+
+	"btn_chips":         "🪙 Фишки",
+	"btn_chip":          "+%d",
+	"btn_slip_clear":    "✖️ Сброс",
+	"btn_slip_half":     "½",
+	"btn_slip_max":      "Макс.",
+	"btn_slip_repeat":   "🔁 Прошлая %d",
+	"btn_slip_double":   "×2 прошлой: %d",
+	"btn_slip_favorite": "⭐ %d",
+	"btn_slip_deal":     "🃏 Раздать %d",
+	"btn_slip_save":     "⭐ В любимые",
+	"btn_slip_unsave":   "☆ Убрать из любимых",
+
+	"slip":             "🪙 Ставка: %d\nЛимиты: %d–%d · баланс: %d",
+	"slip_placed":      "🪙 Ставка %d принята",
+	"slip_fav_added":   "⭐ %d в любимых ставках",
+	"slip_fav_removed": "☆ %d убрана из любимых ставок",
+
+	// Команды
+	"start": "🎰 Добро пожаловать в Blackjack!\n\n" +
+		"💵 Баланс: %s\n\n" +
+		"/play <ставка> — играть\n" +
+		"/bet — собрать ставку из фишек\n" +
+		"/play 100 100 50 — несколько боксов\n" +
+		"/play 100 side=10 — с побочной ставкой 21+3\n" +
+		"/play 100 pairs=10 — с побочной ставкой Perfect Pairs\n" +
+		"/play spanish 100 — Spanish 21\n" +
+		"/play freebet 100 — Free Bet Blackjack\n" +
+		"/play switch 100 — Blackjack Switch, два бокса по 100\n" +
+		"/balance — статистика\n" +
+		"/bonus — ежедневный бонус\n" +
+		"/achievements — достижения\n" +
+		"/tournament — турниры\n" +
+		"/top — топ игроков\n" +
+		"/seed — сид для честной раздачи\n" +
+		"/verify <раунд> — проверить раунд\n" +
+		"/replay <раунд> — повтор раунда\n" +
+		"/lang — язык\n" +
+		"/images — карты картинкой\n" +
+		"/token — токен для HTTP API\n" +
+		"/live — ссылка на трансляцию\n" +
+		"/help — правила",
+	"help": "📖 Правила Blackjack:\n\n" +
+		"🎯 Цель: набрать 21 или больше дилера\n\n" +
+		"📊 Очки: 2-10 номинал, J/Q/K = 10, A = 11 или 1\n\n" +
+		"🎮 Действия:\n" +
+		"• Ещё — взять карту\n" +
+		"• Хватит — остановиться\n" +
+		"• Удвоить — удвоить ставку и взять одну карту\n" +
+		"• Разделить — разделить пару\n\n" +
+		"✂️ Сплит: при двух одинаковых картах можно разделить на две руки. Каждая рука играет отдельно.\n\n" +
+		"📦 Боксы: /play 100 100 50 — до %d боксов со своими ставками, они играются по очереди.\n\n" +
+		"🎰 Blackjack платит x%.1f\n\n" +
+		"%s" +
+		"%s" +
+		"🔐 Честная раздача: перед раундом бот публикует SHA-256 хэш сида сервера. " +
+		"Колода тасуется из сида сервера, вашего сида (/seed) и номера раунда. " +
+		"После раунда сид раскрывается, и его можно проверить через /verify.",
+	"help_side": "🎲 21+3: /play 100 side=10 — побочная ставка на каждый бокс. Две ваши первые карты и открытая карта дилера " +
+		"составляют покерную комбинацию, она оплачивается сразу после раздачи:\n%s\n",
+	"help_pairs": "👯 Perfect Pairs: /play 100 pairs=10 — побочная ставка на то, что две первые карты составят пару:\n%s\n",
+	"help_spanish": "🇪🇸 Spanish 21: /play spanish 100 — колода из 48 карт без десяток. " +
+		"21 игрока выигрывает всегда; 21 из 5 карт платит 3:2, из 6 — 2:1, из 7 и больше — 3:1, " +
+		"6-7-8 и 7-7-7 — 3:2, одной масти 2:1, пиками 3:1 (после удвоения бонусов нет). " +
+		"Можно сдаться на двух первых картах, удвоить после сплита и после удвоения " +
+		"спасти руку: удвоение возвращается, исходная ставка проиграна.\n\n",
+	"help_freebet": "🎁 Free Bet: /play freebet 100 — удвоение на жёстких 9-11 и сплит любой пары, кроме десяток, бесплатные. " +
+		"Бесплатные фишки приносят только выигрыш, без ставки. 22 у дилера — ничья для всех оставшихся рук.\n\n",
+	"help_switch": "🔀 Blackjack Switch: /play switch 100 — два бокса с равными ставками. До первого хода " +
+		"можно поменять местами их вторые карты. Блэкджек платит 1:1, 22 у дилера — ничья для всех оставшихся рук.\n\n",
+	"help_side_line": "• %s — %d:1\n",
+	"help_timeout":   "⏰ На ход даётся %s, потом рука закрывается автоматически.\n\n",
+	"balance": "💰 Баланс: %s\n\n" +
+		"📊 Статистика:\n" +
+		"🎮 Сыграно: %s\n" +
+		"✅ Побед: %d (%.1f%%)\n" +
+		"❌ Поражений: %d\n" +
+		"🤝 Ничьих: %d",
+	"top_empty": "🏆 Пока никто не играл!",
+	"top_title": "🏆 Топ игроков:\n\n",
+	"top_line":  "%s %d 💰 | %s (%.0f%%)\n",
+
+	"bonus":            "🎁 Ежедневный бонус: +%d\n🔥 Серия: %s\n💵 Баланс: %d",
+	"bonus_claimed":    "⏳ Бонус за сегодня уже получен. Следующий через %s",
+	"bailout":          "🆘 Фишки закончились, баланс пополнен до %d. Следующее пополнение возможно через %s",
+	"no_funds_bailout": "❌ Недостаточно средств! Баланс: %d\n🆘 Бесплатное пополнение через %s, /bonus — ежедневный бонус",
+
+	"lang_choose":  "🌐 Выберите язык",
+	"lang_set":     "✅ Язык: русский",
+	"lang_unknown": "❌ Доступные языки: ru, en",
+	"lang_name":    "🇷🇺 Русский",
+
+	"images_on":    "🖼 Игра теперь показывается картинкой стола. /images off — снова текстом",
+	"images_off":   "📝 Игра теперь показывается текстом. /images on — картинкой стола",
+	"images_usage": "❌ Формат: /images, /images on или /images off",
+
+	"token_issued":       "🔑 Токен для HTTP API:\n%s\n\nПередавайте его в заголовке Authorization: Bearer <токен>. Прежний токен больше не действует.",
+	"token_private_only": "🔑 Токен выдаётся только в личном чате с ботом",
+
+	"live_link":     "📺 Трансляция раундов этого чата (WebSocket, JSON):\n%s",
+	"live_disabled": "📺 Трансляция на этом боте не включена",
+
+	"banned": "🚫 Вы заблокированы в игре",
+
+	// Администрирование
+	"admin_usage": "🛠 Команды администратора:\n\n" +
+		"/admin grant <игрок> <сумма> — начислить фишки (отрицательная сумма списывает)\n" +
+		"/admin set <игрок> <сумма> — установить баланс\n" +
+		"/admin ban <игрок> — заблокировать игрока\n" +
+		"/admin unban <игрок> — разблокировать игрока\n" +
+		"/admin player <игрок> — данные игрока\n" +
+		"/admin broadcast <текст> — сообщение всем игрокам\n" +
+		"/admin tournament <старт> <раунды> <фишки> <призы> — запланировать турнир",
+	"admin_invalid_amount":    "❌ Неверная сумма",
+	"admin_not_found":         "❌ Игрок %d не найден",
+	"admin_negative":          "❌ Баланс не может быть отрицательным",
+	"admin_already":           "❌ Игрок %d уже в этом состоянии",
+	"admin_balance":           "✅ Игрок %d: %+d, баланс %d",
+	"admin_banned":            "🚫 Игрок %d заблокирован",
+	"admin_unbanned":          "✅ Игрок %d разблокирован",
+	"admin_player":            "👤 Игрок %d\n💰 Баланс: %d\n🎮 Игр: %d (побед %d, поражений %d, ничьих %d)\n🌐 Язык: %s\n🚫 Заблокирован: %s",
+	"admin_yes":               "да",
+	"admin_no":                "нет",
+	"admin_ledger_title":      "\n\n📒 Журнал:\n",
+	"admin_ledger_line":       "%s %s %+d → %d (админ %d)\n",
+	"admin_broadcast_empty":   "❌ Нет текста сообщения",
+	"admin_broadcast_started": "📣 Рассылка: %s…",
+	"admin_broadcast_done":    "📣 Рассылка завершена: отправлено %d, ошибок %d",
+	"admin_tournament_invalid": "❌ Пример: /admin tournament 2h 10 1000 500,300,100\n" +
+		"Старт: через сколько (2h) или когда по UTC (2026-10-20T18:00); призы за места через запятую",
+	"admin_tournament_created": "🏆 Турнир #%d запланирован: старт %s UTC, %s, фишек: %d, призы %s",
+	"admin_tournament_exists":  "❌ Другой турнир уже запланирован или идет",
+
+	// Ставки и раунд
+	"too_many_boxes":  "❌ Максимум за раунд: %s",
+	"invalid_bet":     "❌ Неверная ставка. Пример: %s %d",
+	"bet_range":       "❌ Ставка от %d до %d",
+	"no_funds":        "❌ Недостаточно средств! Баланс: %d",
+	"round_started":   "🔐 Раунд #%d · хэш: %s\n💰 Ставка: %s | Баланс: %d\n\n%s",
+	"bust_next":       "💥 Перебор на руке %d!\n\n%s",
+	"stand_next":      "✋ Стоим. Переход к руке %d\n\n%s",
+	"double_no_funds": "❌ Недостаточно средств для удвоения",
+	"double_next":     "💰 Удвоено! %s Переход к руке %d\n\n%s",
+	"double_rescue":   "💰 Удвоено! Остановитесь или спасите руку: удвоение вернётся, ставка проиграна\n\n%s",
+	"surrender_next":  "🏳️ Сдались. Переход к руке %d\n\n%s",
+	"switch_done":     "🔀 Вторые карты поменялись местами\n\n%s",
+	"switch_bets":     "❌ Blackjack Switch играется на двух боксах с равными ставками. Пример: /play switch 100",
+	"variant_round":   "%s\n",
+	"split_no_funds":  "❌ Недостаточно средств для сплита",
+	"split_aces":      "✂️ Сплит тузов! По одной карте на каждую руку.",
+	"split_done":      "✂️ Сплит! Теперь у вас %s.\n💰 Общая ставка: %d | Баланс: %d\n\n%s",
+	"game_restored":   "♻️ Бот перезапущен, игра продолжается\n\n%s",
+
+	// Побочные ставки
+	"side_range": "❌ Побочная ставка от %d до %d",
+	"side_box":   " · бокс %d",
+	"side_win":   "\n🎲 %s: %v — %s! +%d",
+	"side_loss":  "\n🎲 %s: %v — без комбинации",
+
+	"poker_flush":           "Флеш",
+	"poker_straight":        "Стрит",
+	"poker_three_of_a_kind": "Тройка",
+	"poker_straight_flush":  "Стрит-флеш",
+	"poker_suited_trips":    "Одномастная тройка",
+
+	"pair_mixed":      "Разноцветная пара",
+	"pair_coloured":   "Одноцветная пара",
+	"pair_perfect":    "Идеальная пара",
+	"timeout_expired": "⏰ Время на ход вышло\n\n",
+
+	// Достижения
+	"achievements_title":   "🏅 Достижения %d/%d:\n\n",
+	"achievement_done":     "✅ %s — %s (%s)\n",
+	"achievement_locked":   "🔒 %s — %s\n",
+	"achievement_unlocked": "🏅 Новое достижение: %s",
+	"table_achievement":    "🏅 %s получает достижение: %s",
+	"balance_achievements": "\n🏅 Достижения: %d/%d",
+
+	"ach_first_win":             "Первая победа",
+	"ach_first_win_desc":        "выиграть раунд",
+	"ach_first_blackjack":       "Натурал",
+	"ach_first_blackjack_desc":  "собрать первый блэкджек",
+	"ach_suited_blackjack":      "Одномастный блэкджек",
+	"ach_suited_blackjack_desc": "блэкджек из туза и десятки одной масти",
+	"ach_win_streak_5":          "Полоса удачи",
+	"ach_win_streak_5_desc":     "выиграть 5 раундов подряд",
+	"ach_double_eleven":         "Удвоение по учебнику",
+	"ach_double_eleven_desc":    "удвоить на 11 и выиграть",
+	"ach_split_four":            "Четыре руки",
+	"ach_split_four_desc":       "разделить бокс на четыре руки и остаться в плюсе",
+
+	// Турниры
+	"tournament_usage": "🏆 Турниры:\n\n" +
+		"/tournament — текущий турнир и таблица\n" +
+		"/tournament join — зарегистрироваться\n" +
+		"/tournament play <ставка> — сыграть турнирный раунд (в личном чате)",
+	"tournament_none": "🏆 Турниров не запланировано",
+	"tournament_scheduled": "🏆 Турнир #%d\n" +
+		"🕒 Старт: %s UTC (через %s)\n" +
+		"🎲 %s, турнирных фишек у каждого: %d\n" +
+		"🎁 Призы: %s\n" +
+		"👥 Зарегистрировано: %s\n\n%s",
+	"tournament_join_hint":    "/tournament join — зарегистрироваться",
+	"tournament_registered":   "✅ Вы зарегистрированы",
+	"tournament_joined":       "✅ %s в турнире #%d. Старт: %s UTC, турнирных фишек: %d",
+	"tournament_closed":       "❌ Регистрация закрыта",
+	"tournament_not_entered":  "❌ Вы не зарегистрированы в этом турнире",
+	"tournament_not_running":  "❌ Сейчас турнир не идет. /tournament — расписание",
+	"tournament_no_rounds":    "🏁 Все турнирные раунды сыграны",
+	"tournament_no_chips":     "❌ Не хватает турнирных фишек на эту ставку",
+	"tournament_round_active": "❌ Сначала доиграйте текущий раунд",
+	"tournament_private_only": "🏆 Турнирные раунды играются в личном чате с ботом",
+	"tournament_started": "🏁 Турнир #%d начался: %s, турнирных фишек у каждого: %d, до %s UTC.\n" +
+		"Основной баланс не меняется. Играть: /tournament play <ставка>",
+	"tournament_round":        "🏆 Турнир #%d · раунд %d/%d\n",
+	"tournament_round_done":   "\n🏆 Турнирные фишки: %d · раунд %d/%d · место %d/%d",
+	"tournament_play_hint":    "\n\n/tournament play <ставка> — играть (осталось раундов: %d)",
+	"tournament_title":        "🏆 Турнир #%d · %s\n",
+	"tournament_until":        "⏳ До %s UTC\n\n",
+	"tournament_finished":     "🏁 Завершен\n\n",
+	"tournament_empty":        "Никто не зарегистрировался",
+	"tournament_line":         "%s %s — %d · %d/%d%s\n",
+	"tournament_prize_suffix": " · 🎁 +%d",
+	"tournament_you":          " 👈",
+	"tournament_over":         "🏁 Турнир #%d завершен! Ваше место: %d из %d",
+	"tournament_prize":        "\n🎁 Приз: %s зачислено на баланс",
+
+	// Отображение рук
+	"hand_box_hand": "🎴 Бокс %d, рука %d:",
+	"hand_box":      "🎴 Бокс %d:",
+	"hand_bet_free": " · %d + 🎁%d бесплатно",
+	"hand_n":        "🎴 Рука %d:",
+	"dealer_hidden": "🃏 Дилер: [%s, ?]",
+	"dealer":        "🃏 Дилер: %v (%d)",
+	"total_win":     "\n💰 Выигрыш: +%s",
+	"end_balance":   "\n💵 Баланс: %d",
+
+	"result_blackjack": "🎰 BLACKJACK! x%.1f",
+	"result_win":       "🎉 Победа!",
+	"result_loss":      "😔 Проигрыш",
+	"reason_charlie":   " — %d-card Charlie",
+	"reason_21wins":    " — 21 бьет 21 дилера",
+	"reason_ties":      " — ничья в пользу дилера",
+	"rule_charlie":     "%d-card Charlie",
+	"rule_21wins":      "21 бьет 21 дилера",
+	"rule_ties":        "ничья в пользу дилера",
+	"result_push":      "🤝 Ничья",
+	"result_surrender": "🏳️ Сдача, возврат %d",
+	"result_bonus":     "🎉 Бонус за 21! +%d",
+
+	"variant_classic": "🃏 Классика",
+	"variant_spanish": "🇪🇸 Spanish 21",
+	"variant_freebet": "🎁 Free Bet",
+	"variant_switch":  "🔀 Blackjack Switch",
+
+	// Честная раздача
+	"reveal":             "\n\n🔓 Раунд #%d\nСид сервера: %s\nПроверка: /verify %d",
+	"reveal_next":        "\n🔐 Хэш следующего раунда: %s",
+	"seed_too_long":      "❌ Сид не длиннее 64 символов",
+	"seed_info":          "🌱 Ваш сид: %s\n🔐 Хэш сида сервера для следующего раунда: %s\n\nСменить сид: /seed <текст>",
+	"round_missing":      "❌ Укажите номер раунда. Пример: %s 42",
+	"round_invalid":      "❌ Неверный номер раунда",
+	"round_not_found":    "❌ Раунд не найден",
+	"round_not_finished": "⏳ Раунд ещё не завершён, сид сервера не раскрыт",
+	"verify_failed":      "❌ Раунд #%d не прошёл проверку: %v",
+	"verify_ok": "✅ Раунд #%d честный\n\n" +
+		"🔐 Хэш: %s\n" +
+		"🔓 Сид сервера: %s\n" +
+		"🌱 Сид клиента: %s\n" +
+		"🃏 Карты: %v",
+
+	// Повтор
+	"replay_no_log":      "❌ Для раунда #%d нет журнала событий",
+	"replay_title":       "🎬 Повтор раунда #%d\n",
+	"replay_failed":      "❌ Раунд #%d не удалось повторить: %v",
+	"replay_hand":        "рука",
+	"replay_hand_n":      "рука %d",
+	"replay_bet":         "💰 Ставка %d",
+	"replay_deal":        "🎴 Раздача: вы %s, дилер %v",
+	"replay_card":        "🎴 %s: %s → %v (%d)",
+	"replay_hit":         "👊 Ещё, %s: %s → %v (%d)",
+	"replay_stand":       "✋ Хватит, %s",
+	"replay_double":      "💰 Удвоение, %s: %s → %v (%d)",
+	"replay_split":       "✂️ Сплит",
+	"replay_switch":      "🔀 Обмен: %v и %v",
+	"replay_surrender":   "🏳️ Сдача, %s",
+	"replay_forfeit":     "⏰ Рука закрыта по таймауту, %s",
+	"replay_reveal":      "🃏 Дилер вскрывает: %v (%d)",
+	"replay_dealer_draw": "🃏 Дилер берёт %s → %v (%d)",
+
+	// Групповой стол
+	"table_help": "🎲 Стол Blackjack на весь чат (до %d мест)\n\n" +
+		"/join — сесть за стол\n" +
+		"/bet <ставка> — поставить (садит за стол автоматически)\n" +
+		"/leave — встать из-за стола\n" +
+		"/table — состояние стола\n" +
+		"/table spanish, /table freebet — правила стола (/table classic — обратно)\n" +
+		"/table rules charlie=5 21wins ties — правила дома (/table rules off — без них)\n" +
+		"/balance — ваш баланс\n" +
+		"/bonus — ежедневный бонус\n" +
+		"/achievements — достижения\n" +
+		"/tournament — турниры\n" +
+		"/lang — язык\n" +
+		"/live — ссылка на трансляцию\n\n" +
+		"После первой ставки идёт отсчёт, затем раздача. Ходят по очереди, у каждого свой баланс.",
+	"table_round":            "🔐 Раунд #%d · хэш: %s",
+	"table_aborted":          "🛑 Бот перезапускается, раунд отменён и ставки возвращены",
+	"table_bailout":          "🆘 У %s закончились фишки, баланс пополнен до %d",
+	"table_no_funds_bailout": "❌ %s: недостаточно средств! Баланс: %d\n🆘 Бесплатное пополнение через %s",
+	"table_left":             "🚪 %s встаёт из-за стола\n\n%s",
+	"table_empty":            "🎲 Стол пуст. /bet <ставка> — сесть и поставить",
+	"table_no_funds":         "❌ %s: недостаточно средств! Баланс: %d",
+	"table_refunded":         "❌ Ошибка, ставки возвращены",
+	"table_turn_of":          "Сейчас ходит %s",
+	"table_bust":             "💥 %s: перебор!",
+	"table_stand":            "✋ %s: стоп",
+	"table_double":           "💰 %s удваивает",
+	"table_split":            "✂️ %s: сплит",
+	"table_surrender":        "🏳️ %s сдаётся",
+	"table_double_no_funds":  "❌ %s: недостаточно средств для удвоения",
+	"table_split_no_funds":   "❌ %s: недостаточно средств для сплита",
+	"table_now":              "\n\n👉 Ходит %s",
+	"table_round_over":       "🏁 Раунд окончен\n\n",
+	"table_player_balance":   "   💵 %s: %d",
+	"table_lobby":            "🎲 Стол: %d/%d\n\n",
+	"table_lobby_variant":    "Правила: %s\n",
+	"table_lobby_rules":      "Правила дома: %s\n",
+	"table_rules":            "🏠 Правила дома: %s",
+	"table_rules_off":        "🏠 Правила дома выключены",
+	"table_rules_usage":      "❌ Правила дома: /table rules charlie=N (N от %d до %d), 21wins, ties — в любом сочетании; /table rules off — без них",
+	"table_variant":          "🎲 Правила стола: %s",
+	"table_variant_usage":    "❌ Правила: /table classic, /table spanish или /table freebet",
+	"table_seat_bet":         "%d. %s — 💰 %d\n",
+	"table_seat_wait":        "%d. %s — ждём ставку\n",
+	"table_countdown":        "\n⏳ Раздача через %s",
+	"table_bet_hint":         "\n/bet <ставка> — сделать ставку",
+	"table_hand_n":           "%s (рука %d)",
+	"table_timeout":          "⏰ %s: время на ход вышло",
+	"table_full":             "❌ Стол заполнен (%d мест)",
+	"table_already_seated":   "❌ Вы уже за столом",
+	"table_not_seated":       "❌ Вы не за столом. /join — сесть",
+	"table_wrong_phase":      "⏳ Идёт раунд, дождитесь его окончания",
+	"table_already_bet":      "❌ Ставка уже сделана",
+	"table_bets_placed":      "❌ Ставки уже сделаны, смените правила после раунда",
+}
@@ -0,0 +1,412 @@
+package ledger
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNotFound     = errors.New("player not found")
+	ErrNegative     = errors.New("balance cannot be negative")
+	ErrAlreadyState = errors.New("player is already in this state")
+
+	ErrClaimed  = errors.New("already claimed")
+	ErrNotBroke = errors.New("player can still afford a bet")
+)
+
+type Kind string
+
+const (
+	KindGrant Kind = "grant"
+	KindSet   Kind = "set"
+	KindBan   Kind = "ban"
+	KindUnban Kind = "unban"
+
+	// начисления самому игроку, AdminID у них 0
+	KindBonus   Kind = "bonus"
+	KindBailout Kind = "bailout"
+	KindPrize   Kind = "prize"
+	KindSideBet Kind = "side_bet"
+
+	// игровые движения баланса: ставки (с удвоениями и сплитами),
+	// выплаты по итогу раунда и возвраты отмененных ставок
+	KindBet    Kind = "bet"
+	KindWin    Kind = "win"
+	KindRefund Kind = "refund"
+)
+
+// Change — одно изменение баланса для Apply; Amount < 0 — списание
+type Change struct {
+	Kind   Kind
+	Amount int
+}
+
+// Entry — одно изменение баланса или бана игрока: действие админа, начисление
+// или игровое движение фишек. Amount — изменение баланса, Balance — баланс после него.
+type Entry struct {
+	ID        int64
+	UserID    int64
+	AdminID   int64
+	Kind      Kind
+	Amount    int
+	Balance   int
+	CreatedAt time.Time
+}
+
+// Repository меняет баланс и бан игрока вместе с записью в журнал —
+// одной транзакцией, так что журнал всегда сходится с балансом. Других путей
+// записи баланса нет: игра меняет его через Apply на сумму, а не пишет целиком.
+type Repository interface {
+	Grant(adminID, userID int64, amount int) (*Entry, error)
+	Set(adminID, userID int64, balance int) (*Entry, error)
+	Ban(adminID, userID int64, banned bool) (*Entry, error)
+	History(userID int64, limit int) ([]Entry, error)
+
+	Bonus(userID int64, now time.Time, base, maxStreak int) (*Entry, int, error)
+	Bailout(userID int64, now time.Time, minBet, amount int, cooldown time.Duration) (*Entry, error)
+	Payout(userID int64, kind Kind, amount int) (*Entry, error)
+	Apply(userID int64, changes ...Change) (int, error)
+}
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Grant начисляет (или при отрицательной сумме списывает) фишки
+func (r *SQLiteRepository) Grant(adminID, userID int64, amount int) (*Entry, error) {
+	return r.change(adminID, userID, KindGrant, func(balance int, _ bool) (int, error) {
+		if balance+amount < 0 {
+			return 0, ErrNegative
+		}
+		return balance + amount, nil
+	})
+}
+
+func (r *SQLiteRepository) Set(adminID, userID int64, balance int) (*Entry, error) {
+	if balance < 0 {
+		return nil, ErrNegative
+	}
+	return r.change(adminID, userID, KindSet, func(int, bool) (int, error) {
+		return balance, nil
+	})
+}
+
+func (r *SQLiteRepository) Ban(adminID, userID int64, banned bool) (*Entry, error) {
+	kind := KindUnban
+	if banned {
+		kind = KindBan
+	}
+
+	return r.change(adminID, userID, kind, func(balance int, was bool) (int, error) {
+		if was == banned {
+			return 0, ErrAlreadyState
+		}
+		return balance, nil
+	})
+}
+
+// change читает игрока, применяет apply и пишет баланс, бан и запись журнала
+func (r *SQLiteRepository) change(adminID, userID int64, kind Kind, apply func(balance int, banned bool) (int, error)) (*Entry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var balance int
+	var banned bool
+	err = tx.QueryRow(`SELECT balance, banned FROM players WHERE user_id = ?`, userID).Scan(&balance, &banned)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player: %w", err)
+	}
+
+	next, err := apply(balance, banned)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case KindBan:
+		banned = true
+	case KindUnban:
+		banned = false
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE players SET balance = ?, banned = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = ?
+	`, next, banned, userID); err != nil {
+		return nil, fmt.Errorf("failed to update player: %w", err)
+	}
+
+	e, err := insert(tx, adminID, userID, kind, next-balance, next)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Bonus выдает ежедневный бонус: base, умноженный на серию дней подряд
+// (не больше maxStreak). Дни считаются по UTC. Возвращает запись и серию.
+func (r *SQLiteRepository) Bonus(userID int64, now time.Time, base, maxStreak int) (*Entry, int, error) {
+	today := now.UTC().Format(time.DateOnly)
+	yesterday := now.UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	// Транзакция начинается с записи: параллельный запрос ждет ее конца
+	// и уже не проходит условие на день
+	var balance, streak int
+	err = tx.QueryRow(`
+		UPDATE players SET
+			bonus_streak = CASE WHEN bonus_day = ? THEN bonus_streak + 1 ELSE 1 END,
+			bonus_day = ?
+		WHERE user_id = ? AND bonus_day <> ?
+		RETURNING balance, bonus_streak
+	`, yesterday, today, userID, today).Scan(&balance, &streak)
+	if err == sql.ErrNoRows {
+		return nil, 0, r.claimError(tx, userID)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to claim bonus: %w", err)
+	}
+
+	amount := base * min(streak, maxStreak)
+	e, err := r.credit(tx, userID, KindBonus, balance, balance+amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+	return e, streak, nil
+}
+
+// Bailout пополняет баланс до amount, если игроку не хватает на минимальную
+// ставку и с прошлой помощи прошло не меньше cooldown
+func (r *SQLiteRepository) Bailout(userID int64, now time.Time, minBet, amount int, cooldown time.Duration) (*Entry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// как и с бонусом, сначала запись; баланс здесь еще прежний
+	var balance int
+	err = tx.QueryRow(`
+		UPDATE players SET bailout_at = ?
+		WHERE user_id = ? AND balance < ? AND bailout_at <= ?
+		RETURNING balance
+	`, now.Unix(), userID, minBet, now.Add(-cooldown).Unix()).Scan(&balance)
+	if err == sql.ErrNoRows {
+		var enough bool
+		err = tx.QueryRow(`SELECT balance >= ? FROM players WHERE user_id = ?`, minBet, userID).Scan(&enough)
+		switch {
+		case err == sql.ErrNoRows:
+			return nil, ErrNotFound
+		case err != nil:
+			return nil, fmt.Errorf("failed to get player: %w", err)
+		case enough:
+			return nil, ErrNotBroke
+		}
+		return nil, ErrClaimed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim bailout: %w", err)
+	}
+
+	e, err := r.credit(tx, userID, KindBailout, balance, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// claimError объясняет, почему начисление не прошло: игрока нет или уже получено
+func (r *SQLiteRepository) claimError(tx *sql.Tx, userID int64) error {
+	var exists bool
+	err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM players WHERE user_id = ?)`, userID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to get player: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return ErrClaimed
+}
+
+// credit ставит новый баланс и пишет начисление в журнал
+func (r *SQLiteRepository) credit(tx *sql.Tx, userID int64, kind Kind, from, to int) (*Entry, error) {
+	if _, err := tx.Exec(`
+		UPDATE players SET balance = ?, updated_at = CURRENT_TIMESTAMP WHERE user_id = ?
+	`, to, userID); err != nil {
+		return nil, fmt.Errorf("failed to update player: %w", err)
+	}
+	return insert(tx, 0, userID, kind, to-from, to)
+}
+
+// Credit начисляет amount в чужой транзакции — для выплат, которые
+// пишутся вместе с другими изменениями, например призов турнира
+func Credit(tx *sql.Tx, userID int64, kind Kind, amount int) (*Entry, error) {
+	var balance int
+	err := tx.QueryRow(`
+		UPDATE players SET balance = balance + ?, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? RETURNING balance
+	`, amount, userID).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update player: %w", err)
+	}
+	return insert(tx, 0, userID, kind, amount, balance)
+}
+
+// Payout начисляет выигрыш вне расчета раунда, например по побочной ставке
+func (r *SQLiteRepository) Payout(userID int64, kind Kind, amount int) (*Entry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	e, err := Credit(tx, userID, kind, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Apply меняет баланс на сумму изменений одной транзакцией, каждое — отдельной
+// записью журнала. Если баланс уйдет в минус, не применяется ничего (ErrNegative).
+// Возвращает баланс после изменений.
+func (r *SQLiteRepository) Apply(userID int64, changes ...Change) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	balance := -1
+	for _, c := range changes {
+		if c.Amount == 0 {
+			continue
+		}
+
+		err := tx.QueryRow(`
+			UPDATE players SET balance = balance + ?, updated_at = CURRENT_TIMESTAMP
+			WHERE user_id = ? AND balance + ? >= 0 RETURNING balance
+		`, c.Amount, userID, c.Amount).Scan(&balance)
+		if err == sql.ErrNoRows {
+			return 0, r.negativeError(tx, userID)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to update player: %w", err)
+		}
+
+		if _, err := insert(tx, 0, userID, c.Kind, c.Amount, balance); err != nil {
+			return 0, err
+		}
+	}
+
+	// все изменения нулевые: баланс не менялся, но вызывающему он нужен
+	if balance < 0 {
+		err := tx.QueryRow(`SELECT balance FROM players WHERE user_id = ?`, userID).Scan(&balance)
+		if err == sql.ErrNoRows {
+			return 0, ErrNotFound
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to get player: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// negativeError объясняет, почему списание не прошло: игрока нет или не хватает фишек
+func (r *SQLiteRepository) negativeError(tx *sql.Tx, userID int64) error {
+	var exists bool
+	err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM players WHERE user_id = ?)`, userID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to get player: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return ErrNegative
+}
+
+func insert(tx *sql.Tx, adminID, userID int64, kind Kind, amount, balance int) (*Entry, error) {
+	e := &Entry{
+		UserID:    userID,
+		AdminID:   adminID,
+		Kind:      kind,
+		Amount:    amount,
+		Balance:   balance,
+		CreatedAt: time.Now(),
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO ledger (user_id, admin_id, kind, amount, balance)
+		VALUES (?, ?, ?, ?, ?)
+	`, e.UserID, e.AdminID, e.Kind, e.Amount, e.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if e.ID, err = res.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("failed to write ledger: %w", err)
+	}
+	return e, nil
+}
+
+// History возвращает последние записи игрока, новые первыми
+func (r *SQLiteRepository) History(userID int64, limit int) ([]Entry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, admin_id, kind, amount, balance, created_at
+		FROM ledger WHERE user_id = ?
+		ORDER BY id DESC LIMIT ?
+	`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.AdminID, &e.Kind, &e.Amount, &e.Balance, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,245 @@
+package live
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"blackjack/internal/game"
+)
+
+const (
+	pingInterval = 30 * time.Second
+	writeTimeout = 10 * time.Second
+
+	// сколько обновлений ждет медленный зритель, прежде чем его отключат
+	sendBuffer = 32
+)
+
+// Hub раздает обновления раундов зрителям чата по WebSocket.
+// Nil-хаб — трансляция выключена, все методы ничего не делают.
+type Hub struct {
+	secret []byte
+
+	mu   sync.Mutex
+	subs map[int64]map[*conn]struct{}
+	// последнее обновление чата — новый зритель сразу видит стол
+	last map[int64][]byte
+}
+
+func NewHub(secret string) *Hub {
+	return &Hub{
+		secret: []byte(secret),
+		subs:   make(map[int64]map[*conn]struct{}),
+		last:   make(map[int64][]byte),
+	}
+}
+
+// Key — ключ доступа к трансляции чата; без него чужой стол не посмотреть
+func (h *Hub) Key(chatID int64) string {
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(strconv.FormatInt(chatID, 10)))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Handler обслуживает GET /live/{chat}?key=<ключ>
+func (h *Hub) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /live/{chat}", h.handleLive)
+	return mux
+}
+
+func (h *Hub) handleLive(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(r.PathValue("chat"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !hmac.Equal([]byte(r.URL.Query().Get("key")), []byte(h.Key(chatID))) {
+		http.Error(w, "invalid key", http.StatusForbidden)
+		return
+	}
+
+	nc, brw, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	c := newConn()
+	h.subscribe(chatID, c)
+	defer h.unsubscribe(chatID, c)
+
+	go c.readLoop(nc, brw.Reader)
+	c.writeLoop(nc, brw.Writer)
+}
+
+// Track подписывает трансляцию чата на события игры и сразу показывает раздачу.
+// names (может быть nil) подписывает руки именами игроков.
+func (h *Hub) Track(chatID int64, g *game.State, names func(*game.Hand) string) {
+	if h == nil {
+		return
+	}
+
+	g.OnEvent = func(ev game.Event) {
+		h.publish(chatID, newUpdate(chatID, g, string(ev.Type), &ev, names))
+	}
+	h.publish(chatID, newUpdate(chatID, g, UpdateRound, nil, names))
+}
+
+// Settle публикует итог раунда: результаты и выплаты по рукам
+func (h *Hub) Settle(chatID int64, g *game.State, names func(*game.Hand) string) {
+	if h == nil {
+		return
+	}
+	h.publish(chatID, newUpdate(chatID, g, UpdateSettle, nil, names))
+}
+
+func (h *Hub) publish(chatID int64, u Update) {
+	data, err := json.Marshal(u)
+	if err != nil {
+		log.Printf("Failed to encode live update: %v", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last[chatID] = data
+	for c := range h.subs[chatID] {
+		c.send(data)
+	}
+}
+
+func (h *Hub) subscribe(chatID int64, c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subs[chatID] == nil {
+		h.subs[chatID] = make(map[*conn]struct{})
+	}
+	h.subs[chatID][c] = struct{}{}
+
+	if data, ok := h.last[chatID]; ok {
+		c.send(data)
+	}
+}
+
+func (h *Hub) unsubscribe(chatID int64, c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subs[chatID], c)
+	if len(h.subs[chatID]) == 0 {
+		delete(h.subs, chatID)
+	}
+}
+
+// Close отключает всех зрителей при остановке бота
+func (h *Hub) Close() {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, subs := range h.subs {
+		for c := range subs {
+			c.close()
+		}
+	}
+}
+
+// conn — одно WebSocket-соединение зрителя
+type conn struct {
+	out  chan []byte
+	ctrl chan frame
+	done chan struct{}
+	once sync.Once
+}
+
+func newConn() *conn {
+	return &conn{
+		out:  make(chan []byte, sendBuffer),
+		ctrl: make(chan frame, 4),
+		done: make(chan struct{}),
+	}
+}
+
+// send не блокирует: зритель, который не успевает читать, отключается
+func (c *conn) send(data []byte) {
+	select {
+	case c.out <- data:
+	default:
+		c.close()
+	}
+}
+
+func (c *conn) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+func (c *conn) readLoop(nc net.Conn, r *bufio.Reader) {
+	defer c.close()
+
+	for {
+		nc.SetReadDeadline(time.Now().Add(2 * pingInterval))
+		f, err := readFrame(r)
+		if err != nil {
+			return
+		}
+
+		switch f.op {
+		case opClose:
+			return
+		case opPing:
+			select {
+			case c.ctrl <- frame{op: opPong, payload: f.payload}:
+			default:
+			}
+		}
+	}
+}
+
+func (c *conn) writeLoop(nc net.Conn, w *bufio.Writer) {
+	defer nc.Close()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		var f frame
+		select {
+		case <-c.done:
+			nc.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if writeFrame(w, opClose, nil) == nil {
+				w.Flush()
+			}
+			return
+		case data := <-c.out:
+			f = frame{op: opText, payload: data}
+		case f = <-c.ctrl:
+		case <-ticker.C:
+			f = frame{op: opPing}
+		}
+
+		nc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		err := writeFrame(w, f.op, f.payload)
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			c.close()
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package live
+
+import "blackjack/internal/game"
+
+// Типы обновлений помимо событий игры (deal, hit, dealer_draw, ...)
+const (
+	// UpdateRound — новая раздача или подключение к идущему раунду
+	UpdateRound = "round"
+	// UpdateSettle — раунд рассчитан
+	UpdateSettle = "settle"
+)
+
+// Update — сообщение трансляции: что произошло и стол после этого
+type Update struct {
+	Type    string      `json:"type"`
+	ChatID  int64       `json:"chat_id"`
+	RoundID int64       `json:"round_id"`
+	Event   *game.Event `json:"event,omitempty"`
+
+	Active      bool       `json:"active"`
+	CurrentHand int        `json:"current_hand"`
+	Hands       []HandView `json:"hands"`
+	Dealer      DealerView `json:"dealer"`
+}
+
+type HandView struct {
+	Box    int      `json:"box"`
+	Player string   `json:"player,omitempty"`
+	Cards  []string `json:"cards"`
+	Score  int      `json:"score"`
+	Bet    int      `json:"bet"`
+	Stand  bool     `json:"stand"`
+	Bust   bool     `json:"bust"`
+
+	// только в settle
+	Result string `json:"result,omitempty"`
+	Payout int    `json:"payout,omitempty"`
+}
+
+type DealerView struct {
+	Cards  []string `json:"cards"`
+	Score  int      `json:"score"`
+	Hidden bool     `json:"hidden"`
+}
+
+// newUpdate снимает состояние стола; пока раунд идет, закрытая карта дилера
+// не попадает ни в руки, ни в события
+func newUpdate(chatID int64, g *game.State, typ string, ev *game.Event, names func(*game.Hand) string) Update {
+	u := Update{
+		Type:        typ,
+		ChatID:      chatID,
+		RoundID:     g.RoundID,
+		Event:       ev,
+		Active:      g.IsActive,
+		CurrentHand: g.CurrentHand,
+	}
+
+	if ev != nil && g.IsActive && ev.Hand == game.DealerHand {
+		hidden := *ev
+		hidden.Card = ""
+		u.Event = &hidden
+	}
+
+	for _, hand := range g.Hands {
+		hv := HandView{
+			Box:   hand.Box,
+			Cards: hand.Cards,
+			Score: hand.Score(),
+			Bet:   hand.Bet,
+			Stand: hand.IsStand,
+			Bust:  hand.IsBust,
+		}
+		if names != nil {
+			hv.Player = names(hand)
+		}
+		if typ == UpdateSettle {
+			result, payout := g.HandResult(hand)
+			hv.Result = result.Name()
+			hv.Payout = payout
+		}
+		u.Hands = append(u.Hands, hv)
+	}
+
+	if g.IsActive && len(g.DealerCards) > 0 {
+		up := g.DealerCards[:1]
+		u.Dealer = DealerView{Cards: up, Score: game.CalculateScore(up), Hidden: true}
+	} else {
+		u.Dealer = DealerView{Cards: g.DealerCards, Score: g.DealerScore()}
+	}
+
+	return u
+}
@@ -0,0 +1,163 @@
+package live
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Минимальный WebSocket-сервер (RFC 6455) на стандартной библиотеке:
+// трансляция только отправляет, от клиента принимаются лишь служебные кадры.
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// клиенту писать нечего, поэтому большие кадры не принимаем
+const maxFrameSize = 4096
+
+var (
+	errUnmasked = errors.New("client frame is not masked")
+	errTooLarge = errors.New("frame too large")
+)
+
+type frame struct {
+	op      byte
+	payload []byte
+}
+
+// upgrade выполняет рукопожатие и забирает соединение у net/http
+func upgrade(w http.ResponseWriter, r *http.Request) (net.Conn, *bufio.ReadWriter, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return nil, nil, errors.New("not a websocket request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, nil, errors.New("missing websocket key")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket is not supported", http.StatusInternalServerError)
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	nc, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+	if err := brw.Flush(); err != nil {
+		nc.Close()
+		return nil, nil, err
+	}
+	return nc, brw, nil
+}
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerContains ищет токен в заголовке вида "keep-alive, Upgrade"
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeFrame пишет один кадр без маски — так отправляет сервер
+func writeFrame(w io.Writer, op byte, payload []byte) error {
+	hdr := make([]byte, 0, 10)
+	hdr = append(hdr, 0x80|op)
+
+	switch n := len(payload); {
+	case n < 126:
+		hdr = append(hdr, byte(n))
+	case n <= 0xFFFF:
+		hdr = append(hdr, 126)
+		hdr = binary.BigEndian.AppendUint16(hdr, uint16(n))
+	default:
+		hdr = append(hdr, 127)
+		hdr = binary.BigEndian.AppendUint64(hdr, uint64(n))
+	}
+
+	if _, err := w.Write(hdr); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// readFrame читает кадр клиента; кадры клиента всегда замаскированы
+func readFrame(r io.Reader) (frame, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return frame{}, err
+	}
+
+	op := hdr[0] & 0x0F
+	if hdr[1]&0x80 == 0 {
+		return frame{}, errUnmasked
+	}
+
+	n := uint64(hdr[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return frame{}, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return frame{}, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxFrameSize {
+		return frame{}, errTooLarge
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return frame{}, err
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return frame{}, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return frame{op: op, payload: payload}, nil
+}
@@ -2,21 +2,50 @@ package player
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
+var ErrNotFound = errors.New("player not found")
+
+// MaxFavoriteBets — сколько любимых ставок помнить; новая вытесняет самую старую
+const MaxFavoriteBets = 4
+
+// Player — кошелек и статистика пользователя Telegram (одни и те же в личке и в группах)
 type Player struct {
-	ChatID  int64
+	UserID  int64
 	Balance int
 	Wins    int
 	Losses  int
 	Draws   int
 	Games   int
 	LastBet int
+
+	// побед подряд; ничья серию не прерывает
+	WinStreak int
+
+	// любимые ставки для клавиатуры фишек, новые в конце
+	FavoriteBets []int
+
+	// показывать игру картинкой стола вместо текста
+	CardImages bool
+
+	ClientSeed string
+	Language   string
+
+	// Banned, бонус и помощь банкроту меняются только через ledger, Save их не пишет
+	Banned      bool
+	BonusDay    string
+	BonusStreak int
+	BailoutAt   time.Time
 }
 
 type Stats struct {
-	ChatID  int64
+	UserID  int64
 	Balance int
 	Wins    int
 	Games   int
@@ -24,9 +53,11 @@ type Stats struct {
 }
 
 type Repository interface {
-	GetOrCreate(chatID int64, startBalance, defaultBet int) (*Player, error)
+	GetOrCreate(userID int64, startBalance, defaultBet int) (*Player, error)
+	Get(userID int64) (*Player, error)
 	Save(player *Player) error
 	GetTopByBalance(limit int) ([]Stats, error)
+	UserIDs() ([]int64, error)
 }
 
 type SQLiteRepository struct {
@@ -37,25 +68,15 @@ func NewRepository(db *sql.DB) *SQLiteRepository {
 	return &SQLiteRepository{db: db}
 }
 
-func (r *SQLiteRepository) GetOrCreate(chatID int64, startBalance, defaultBet int) (*Player, error) {
-	player := &Player{ChatID: chatID}
-
-	err := r.db.QueryRow(`
-		SELECT balance, wins, losses, draws, games, last_bet
-		FROM players WHERE chat_id = ?
-	`, chatID).Scan(
-		&player.Balance, &player.Wins, &player.Losses,
-		&player.Draws, &player.Games, &player.LastBet,
-	)
-
-	if err == sql.ErrNoRows {
-		player.Balance = startBalance
-		player.LastBet = defaultBet
+func (r *SQLiteRepository) GetOrCreate(userID int64, startBalance, defaultBet int) (*Player, error) {
+	player, err := r.Get(userID)
+	if errors.Is(err, ErrNotFound) {
+		player = &Player{UserID: userID, Balance: startBalance, LastBet: defaultBet}
 
 		_, err = r.db.Exec(`
-			INSERT INTO players (chat_id, balance, last_bet)
+			INSERT INTO players (user_id, balance, last_bet)
 			VALUES (?, ?, ?)
-		`, chatID, player.Balance, player.LastBet)
+		`, userID, player.Balance, player.LastBet)
 
 		if err != nil {
 			return nil, fmt.Errorf("failed to create player: %w", err)
@@ -63,21 +84,60 @@ func (r *SQLiteRepository) GetOrCreate(chatID int64, startBalance, defaultBet in
 		return player, nil
 	}
 
+	if err != nil {
+		return nil, err
+	}
+
+	return player, nil
+}
+
+// Get возвращает существующего игрока, не создавая нового
+func (r *SQLiteRepository) Get(userID int64) (*Player, error) {
+	player := &Player{UserID: userID}
+	var bailoutAt int64
+	var favorites string
+
+	err := r.db.QueryRow(`
+		SELECT balance, wins, losses, draws, games, last_bet, win_streak, client_seed, language,
+			banned, bonus_day, bonus_streak, bailout_at, favorite_bets, card_images
+		FROM players WHERE user_id = ?
+	`, userID).Scan(
+		&player.Balance, &player.Wins, &player.Losses,
+		&player.Draws, &player.Games, &player.LastBet, &player.WinStreak, &player.ClientSeed, &player.Language,
+		&player.Banned, &player.BonusDay, &player.BonusStreak, &bailoutAt, &favorites, &player.CardImages,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get player: %w", err)
 	}
 
+	if bailoutAt > 0 {
+		player.BailoutAt = time.Unix(bailoutAt, 0)
+	}
+	for _, s := range strings.Split(favorites, ",") {
+		if bet, err := strconv.Atoi(s); err == nil && bet > 0 {
+			player.FavoriteBets = append(player.FavoriteBets, bet)
+		}
+	}
 	return player, nil
 }
 
+// Save пишет статистику и настройки игрока. Баланс меняется только через
+// ledger изменениями на сумму: запись загруженного раньше баланса затерла бы
+// начисления, сделанные за это время.
 func (r *SQLiteRepository) Save(player *Player) error {
 	_, err := r.db.Exec(`
 		UPDATE players SET
-			balance = ?, wins = ?, losses = ?, draws = ?,
-			games = ?, last_bet = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE chat_id = ?
-	`, player.Balance, player.Wins, player.Losses, player.Draws,
-		player.Games, player.LastBet, player.ChatID)
+			wins = ?, losses = ?, draws = ?,
+			games = ?, last_bet = ?, win_streak = ?, client_seed = ?, language = ?, favorite_bets = ?,
+			card_images = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = ?
+	`, player.Wins, player.Losses, player.Draws,
+		player.Games, player.LastBet, player.WinStreak, player.ClientSeed, player.Language,
+		joinBets(player.FavoriteBets), player.CardImages, player.UserID)
 
 	if err != nil {
 		return fmt.Errorf("failed to save player: %w", err)
@@ -87,7 +147,7 @@ func (r *SQLiteRepository) Save(player *Player) error {
 
 func (r *SQLiteRepository) GetTopByBalance(limit int) ([]Stats, error) {
 	rows, err := r.db.Query(`
-		SELECT chat_id, balance, wins, games
+		SELECT user_id, balance, wins, games
 		FROM players
 		WHERE games > 0
 		ORDER BY balance DESC
@@ -101,7 +161,7 @@ func (r *SQLiteRepository) GetTopByBalance(limit int) ([]Stats, error) {
 	var stats []Stats
 	for rows.Next() {
 		var s Stats
-		if err := rows.Scan(&s.ChatID, &s.Balance, &s.Wins, &s.Games); err != nil {
+		if err := rows.Scan(&s.UserID, &s.Balance, &s.Wins, &s.Games); err != nil {
 			return nil, err
 		}
 		if s.Games > 0 {
@@ -113,6 +173,40 @@ func (r *SQLiteRepository) GetTopByBalance(limit int) ([]Stats, error) {
 	return stats, rows.Err()
 }
 
+// UserIDs — все незабаненные игроки, например для рассылки
+func (r *SQLiteRepository) UserIDs() ([]int64, error) {
+	rows, err := r.db.Query(`SELECT user_id FROM players WHERE banned = 0 ORDER BY user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get players: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Settle считает раунд одной игрой: победой, если выигранных рук больше,
+// чем проигранных. Выигрыш зачисляется отдельно, через ledger.
+func (p *Player) Settle(wins, losses int) {
+	if wins > losses {
+		p.Wins++
+		p.WinStreak++
+	} else if losses > wins {
+		p.Losses++
+		p.WinStreak = 0
+	} else {
+		p.Draws++
+	}
+	p.Games++
+}
+
 func (p *Player) AddWin(winAmount int) {
 	p.Balance += winAmount
 	p.Wins++
@@ -130,15 +224,38 @@ func (p *Player) AddDraw(betAmount int) {
 	p.Games++
 }
 
-func (p *Player) PlaceBet(amount int) bool {
-	if amount > p.Balance {
+// ToggleFavorite добавляет ставку в любимые или убирает, если она там уже есть;
+// возвращает true, если ставка добавлена
+func (p *Player) ToggleFavorite(amount int) bool {
+	if i := slices.Index(p.FavoriteBets, amount); i >= 0 {
+		p.FavoriteBets = slices.Delete(p.FavoriteBets, i, i+1)
 		return false
 	}
-	p.Balance -= amount
-	p.LastBet = amount
+
+	p.FavoriteBets = append(p.FavoriteBets, amount)
+	if len(p.FavoriteBets) > MaxFavoriteBets {
+		p.FavoriteBets = p.FavoriteBets[len(p.FavoriteBets)-MaxFavoriteBets:]
+	}
 	return true
 }
 
+func joinBets(bets []int) string {
+	parts := make([]string, len(bets))
+	for i, b := range bets {
+		parts[i] = strconv.Itoa(b)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Seed — сид клиента для честной раздачи; по умолчанию ID пользователя,
+// пока игрок не задал свой через /seed
+func (p *Player) Seed() string {
+	if p.ClientSeed != "" {
+		return p.ClientSeed
+	}
+	return strconv.FormatInt(p.UserID, 10)
+}
+
 func (p *Player) CanAfford(amount int) bool {
 	return p.Balance >= amount
 }
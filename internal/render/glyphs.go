@@ -0,0 +1,179 @@
+package render
+
+// Рисунки знаков: '#' — закрашенный пиксель. Ранги и цифры 5×7, масти 7×7.
+var glyphs = map[rune][]string{
+	'0': {
+		".###.",
+		"#...#",
+		"#..##",
+		"#.#.#",
+		"##..#",
+		"#...#",
+		".###.",
+	},
+	'1': {
+		"..#..",
+		".##..",
+		"..#..",
+		"..#..",
+		"..#..",
+		"..#..",
+		".###.",
+	},
+	'2': {
+		".###.",
+		"#...#",
+		"....#",
+		"...#.",
+		"..#..",
+		".#...",
+		"#####",
+	},
+	'3': {
+		"####.",
+		"....#",
+		"....#",
+		".###.",
+		"....#",
+		"....#",
+		"####.",
+	},
+	'4': {
+		"...#.",
+		"..##.",
+		".#.#.",
+		"#..#.",
+		"#####",
+		"...#.",
+		"...#.",
+	},
+	'5': {
+		"#####",
+		"#....",
+		"####.",
+		"....#",
+		"....#",
+		"#...#",
+		".###.",
+	},
+	'6': {
+		".###.",
+		"#....",
+		"#....",
+		"####.",
+		"#...#",
+		"#...#",
+		".###.",
+	},
+	'7': {
+		"#####",
+		"....#",
+		"...#.",
+		"..#..",
+		".#...",
+		".#...",
+		".#...",
+	},
+	'8': {
+		".###.",
+		"#...#",
+		"#...#",
+		".###.",
+		"#...#",
+		"#...#",
+		".###.",
+	},
+	'9': {
+		".###.",
+		"#...#",
+		"#...#",
+		".####",
+		"....#",
+		"....#",
+		".###.",
+	},
+	'A': {
+		".###.",
+		"#...#",
+		"#...#",
+		"#####",
+		"#...#",
+		"#...#",
+		"#...#",
+	},
+	'J': {
+		"..###",
+		"...#.",
+		"...#.",
+		"...#.",
+		"...#.",
+		"#..#.",
+		".##..",
+	},
+	'Q': {
+		".###.",
+		"#...#",
+		"#...#",
+		"#...#",
+		"#.#.#",
+		"#..#.",
+		".##.#",
+	},
+	'K': {
+		"#...#",
+		"#..#.",
+		"#.#..",
+		"##...",
+		"#.#..",
+		"#..#.",
+		"#...#",
+	},
+	'/': {
+		"....#",
+		"....#",
+		"...#.",
+		"..#..",
+		".#...",
+		"#....",
+		"#....",
+	},
+	'♠': {
+		"...#...",
+		"..###..",
+		".#####.",
+		"#######",
+		"#######",
+		"..#.#..",
+		".#####.",
+	},
+	'♥': {
+		".##.##.",
+		"#######",
+		"#######",
+		"#######",
+		".#####.",
+		"..###..",
+		"...#...",
+	},
+	'♦': {
+		"...#...",
+		"..###..",
+		".#####.",
+		"#######",
+		".#####.",
+		"..###..",
+		"...#...",
+	},
+	'♣': {
+		"..###..",
+		"..###..",
+		"##.#.##",
+		"#######",
+		"##.#.##",
+		"...#...",
+		".#####.",
+	},
+}
+
+// glyphHeight — высота всех знаков в пикселях рисунка
+const glyphHeight = 7
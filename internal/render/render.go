@@ -0,0 +1,258 @@
+// Package render рисует стол игры картинкой PNG: карты дилера и всех рук
+// игрока, ставки и очки. Только стандартная библиотека, рисунки карт — в glyphs.go.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+
+	"blackjack/internal/game"
+)
+
+// Размеры в пикселях картинки
+const (
+	cardW   = 64
+	cardH   = 90
+	cardGap = 8
+	rowH    = cardH + 24
+	margin  = 20
+	marker  = 20  // место под отметку текущей руки
+	sideW   = 190 // очки и ставка справа от карт
+	minW    = 480
+)
+
+var (
+	felt      = color.RGBA{0x0b, 0x5d, 0x2e, 0xff}
+	feltLine  = color.RGBA{0x13, 0x7a, 0x3f, 0xff}
+	cardFace  = color.RGBA{0xfb, 0xfb, 0xf7, 0xff}
+	cardEdge  = color.RGBA{0x9a, 0x9a, 0x9a, 0xff}
+	cardBack  = color.RGBA{0x1f, 0x3f, 0x9e, 0xff}
+	backLine  = color.RGBA{0x6d, 0x8c, 0xe6, 0xff}
+	black     = color.RGBA{0x1a, 0x1a, 0x1a, 0xff}
+	red       = color.RGBA{0xc8, 0x1e, 0x28, 0xff}
+	white     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	gold      = color.RGBA{0xf2, 0xc1, 0x2e, 0xff}
+	badgeGrey = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	badgeWin  = color.RGBA{0x2e, 0x9e, 0x4f, 0xff}
+	badgeLoss = color.RGBA{0xb0, 0x2a, 0x2a, 0xff}
+	badgePush = color.RGBA{0x6b, 0x6b, 0x6b, 0xff}
+	chipColor = color.RGBA{0xd9, 0x3b, 0x3b, 0xff}
+)
+
+// Table рисует игру g: вторая карта дилера закрыта, пока игра идет;
+// после расчета очки рук окрашены по итогу
+func Table(g *game.State) ([]byte, error) {
+	cards := len(g.DealerCards)
+	for _, h := range g.Hands {
+		cards = max(cards, len(h.Cards))
+	}
+	width := max(minW, 2*margin+marker+cards*(cardW+cardGap)+sideW)
+	height := 2*margin + rowH*(len(g.Hands)+1) - (rowH - cardH)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(felt), image.Point{}, draw.Src)
+
+	// дилер
+	x, y := margin+marker, margin
+	hidden := g.IsActive && len(g.DealerCards) > 1
+	for i, card := range g.DealerCards {
+		if hidden && i == 1 {
+			drawBack(img, x, y)
+		} else {
+			drawCard(img, x, y, card)
+		}
+		x += cardW + cardGap
+	}
+	if len(g.DealerCards) > 0 {
+		shown := g.DealerCards
+		if hidden {
+			shown = shown[:1]
+		}
+		drawBadge(img, x+8, y+cardH/2-18, total(shown, g.IsActive), badgeGrey)
+	}
+
+	// разделитель между дилером и игроком
+	y += rowH
+	fillRect(img, image.Rect(margin, y-14, width-margin, y-12), feltLine)
+
+	for i, hand := range g.Hands {
+		if g.IsActive && i == g.CurrentHand {
+			drawMarker(img, margin, y+cardH/2)
+		}
+
+		x = margin + marker
+		for _, card := range hand.Cards {
+			drawCard(img, x, y, card)
+			x += cardW + cardGap
+		}
+
+		drawBadge(img, x+8, y+8, total(hand.Cards, g.IsActive), handColor(g, hand))
+		drawChip(img, x+24, y+cardH-22)
+		drawText(img, x+46, y+cardH-29, strconv.Itoa(hand.Bet), 2, white)
+		y += rowH
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode table: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// total — очки руки; мягкая рука, пока игра идет, — обоими значениями: "7/17"
+func total(cards []string, active bool) string {
+	score := game.CalculateScore(cards)
+	if active && game.IsSoft(cards) && score < 21 {
+		return fmt.Sprintf("%d/%d", score-10, score)
+	}
+	return strconv.Itoa(score)
+}
+
+// handColor — цвет очков руки: по итогу после расчета, золотой для блэкджека
+func handColor(g *game.State, hand *game.Hand) color.RGBA {
+	if g.IsActive {
+		switch {
+		case hand.IsBust:
+			return badgeLoss
+		case hand.IsBlackjack():
+			return gold
+		}
+		return badgeGrey
+	}
+
+	switch result, _ := g.HandResult(hand); result {
+	case game.ResultBlackjack:
+		return gold
+	case game.ResultPlayerWin, game.ResultBonus:
+		return badgeWin
+	case game.ResultPush:
+		return badgePush
+	}
+	return badgeLoss
+}
+
+func drawCard(img *image.RGBA, x, y int, card string) {
+	r := image.Rect(x, y, x+cardW, y+cardH)
+	fillRoundRect(img, r, 7, cardEdge)
+	fillRoundRect(img, r.Inset(1), 6, cardFace)
+
+	ink := black
+	suit := game.Suit(card)
+	if suit == "♥" || suit == "♦" {
+		ink = red
+	}
+
+	rank := game.Rank(card)
+	if rank == "10" {
+		// «10» шире карты при обычном масштабе
+		drawText(img, x+4, y+6, rank, 2, ink)
+	} else {
+		drawText(img, x+6, y+6, rank, 3, ink)
+	}
+
+	if suit != "" {
+		drawText(img, x+6, y+32, suit, 2, ink)
+		drawText(img, x+cardW-7*4-6, y+cardH-7*4-6, suit, 4, ink)
+	}
+}
+
+func drawBack(img *image.RGBA, x, y int) {
+	r := image.Rect(x, y, x+cardW, y+cardH)
+	fillRoundRect(img, r, 7, cardEdge)
+	fillRoundRect(img, r.Inset(1), 6, white)
+	inner := r.Inset(5)
+	fillRoundRect(img, inner, 4, cardBack)
+
+	// диагональная сетка рубашки
+	for py := inner.Min.Y; py < inner.Max.Y; py++ {
+		for px := inner.Min.X; px < inner.Max.X; px++ {
+			if (px+py)%10 == 0 || (px-py+1000)%10 == 0 {
+				img.SetRGBA(px, py, backLine)
+			}
+		}
+	}
+}
+
+// drawBadge — очки в скругленной плашке
+func drawBadge(img *image.RGBA, x, y int, text string, bg color.RGBA) {
+	w := textWidth(text, 3) + 16
+	fillRoundRect(img, image.Rect(x, y, x+w, y+glyphHeight*3+14), 8, bg)
+	drawText(img, x+8, y+7, text, 3, white)
+}
+
+// drawChip — фишка рядом со ставкой
+func drawChip(img *image.RGBA, cx, cy int) {
+	fillCircle(img, cx, cy, 14, white)
+	fillCircle(img, cx, cy, 12, chipColor)
+	fillCircle(img, cx, cy, 7, white)
+	fillCircle(img, cx, cy, 5, chipColor)
+}
+
+// drawMarker — треугольник у текущей руки
+func drawMarker(img *image.RGBA, x, cy int) {
+	for dx := 0; dx < 12; dx++ {
+		for dy := -(12 - dx); dy <= 12-dx; dy++ {
+			img.SetRGBA(x+dx, cy+dy, gold)
+		}
+	}
+}
+
+// drawText пишет знаки из glyphs, каждый пиксель рисунка — квадрат scale×scale
+func drawText(img *image.RGBA, x, y int, text string, scale int, c color.RGBA) {
+	for _, ch := range text {
+		glyph, ok := glyphs[ch]
+		if !ok {
+			continue
+		}
+		for gy, row := range glyph {
+			for gx, px := range row {
+				if px == '#' {
+					fillRect(img, image.Rect(x+gx*scale, y+gy*scale, x+(gx+1)*scale, y+(gy+1)*scale), c)
+				}
+			}
+		}
+		x += (len(glyph[0]) + 1) * scale
+	}
+}
+
+func textWidth(text string, scale int) int {
+	w := 0
+	for _, ch := range text {
+		if glyph, ok := glyphs[ch]; ok {
+			w += (len(glyph[0]) + 1) * scale
+		}
+	}
+	return max(0, w-scale)
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func fillRoundRect(img *image.RGBA, r image.Rectangle, radius int, c color.RGBA) {
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			// расстояние до ближайшего центра скругления
+			dx := max(r.Min.X+radius-px, px-(r.Max.X-1-radius), 0)
+			dy := max(r.Min.Y+radius-py, py-(r.Max.Y-1-radius), 0)
+			if dx*dx+dy*dy <= radius*radius {
+				img.SetRGBA(px, py, c)
+			}
+		}
+	}
+}
+
+func fillCircle(img *image.RGBA, cx, cy, radius int, c color.RGBA) {
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if dx*dx+dy*dy <= radius*radius {
+				img.SetRGBA(cx+dx, cy+dy, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package round
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"blackjack/internal/fair"
+	"blackjack/internal/game"
+)
+
+// ErrStarted — раунд уже начат: бот и API делят личные чаты и могут взять
+// один и тот же ожидающий раунд
+var ErrStarted = errors.New("round already started")
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusActive   Status = "active"
+	StatusFinished Status = "finished"
+)
+
+// Round — запись о раунде для проверки честности раздачи
+type Round struct {
+	ID         int64
+	ChatID     int64
+	ServerSeed string
+	Commitment string
+	ClientSeed string
+	Bet        int
+	Cards      []string
+	Events     []game.Event
+	SideBets   []game.SideResult
+	Status     Status
+	CreatedAt  time.Time
+
+	// раунд начат через HTTP API
+	API bool
+}
+
+// Variant — правила раунда, записанные в его ставках
+func (r *Round) Variant() game.Variant {
+	if len(r.Events) == 0 {
+		return game.VariantClassic
+	}
+	return r.Events[0].Variant
+}
+
+type Repository interface {
+	Next(chatID int64) (*Round, error)
+	Start(r *Round, clientSeed string, bet int) error
+	Save(id int64, cards []string, events []game.Event) error
+	SaveSideBets(id int64, results []game.SideResult) error
+	Finish(id int64, cards []string, events []game.Event) (*Round, error)
+	Get(id int64) (*Round, error)
+	Active() ([]*Round, error)
+	History(chatID int64, limit int) ([]*Round, error)
+}
+
+// rowScanner — общее у *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Next возвращает ожидающий раунд чата, создавая его с новым сидом при необходимости.
+// Коммитмент ожидающего раунда можно показывать игроку заранее.
+func (r *SQLiteRepository) Next(chatID int64) (*Round, error) {
+	rnd, err := r.scan(r.db.QueryRow(`
+		SELECT id, chat_id, server_seed, commitment, client_seed, bet, cards, events, side_bets, status, created_at, api
+		FROM rounds WHERE chat_id = ? AND status = ?
+		ORDER BY id DESC LIMIT 1
+	`, chatID, StatusPending))
+	if err == nil {
+		return rnd, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get pending round: %w", err)
+	}
+
+	seed, err := fair.GenerateServerSeed()
+	if err != nil {
+		return nil, err
+	}
+
+	rnd = &Round{
+		ChatID:     chatID,
+		ServerSeed: seed,
+		Commitment: fair.Commit(seed),
+		Status:     StatusPending,
+		CreatedAt:  time.Now(),
+	}
+
+	res, err := r.db.Exec(`
+		INSERT INTO rounds (chat_id, server_seed, commitment, status)
+		VALUES (?, ?, ?, ?)
+	`, rnd.ChatID, rnd.ServerSeed, rnd.Commitment, rnd.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create round: %w", err)
+	}
+
+	if rnd.ID, err = res.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("failed to create round: %w", err)
+	}
+	return rnd, nil
+}
+
+// Start начинает ожидающий раунд, rnd.API отмечает раунд API;
+// если раунд уже начал другой процесс — ErrStarted
+func (r *SQLiteRepository) Start(rnd *Round, clientSeed string, bet int) error {
+	res, err := r.db.Exec(`
+		UPDATE rounds SET client_seed = ?, bet = ?, status = ?, api = ?
+		WHERE id = ? AND status = ?
+	`, clientSeed, bet, StatusActive, rnd.API, rnd.ID, StatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to start round: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to start round: %w", err)
+	} else if n == 0 {
+		return ErrStarted
+	}
+
+	rnd.ClientSeed = clientSeed
+	rnd.Bet = bet
+	rnd.Status = StatusActive
+	return nil
+}
+
+// Begin берет ожидающий раунд чата и начинает его; api — раунд HTTP API. Если этот
+// раунд тем временем начал другой процесс, берется следующий — сид и nonce не используются дважды.
+func Begin(r Repository, chatID int64, api bool, clientSeed string, bet int) (*Round, error) {
+	for {
+		rnd, err := r.Next(chatID)
+		if err != nil {
+			return nil, err
+		}
+		rnd.API = api
+
+		err = r.Start(rnd, clientSeed, bet)
+		if err == nil {
+			return rnd, nil
+		}
+		if !errors.Is(err, ErrStarted) {
+			return nil, err
+		}
+	}
+}
+
+// Save сохраняет ход незавершенного раунда, чтобы доиграть его после перезапуска
+func (r *SQLiteRepository) Save(id int64, cards []string, events []game.Event) error {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE rounds SET cards = ?, events = ?
+		WHERE id = ? AND status = ?
+	`, strings.Join(cards, ","), string(data), id, StatusActive)
+	if err != nil {
+		return fmt.Errorf("failed to save round: %w", err)
+	}
+	return nil
+}
+
+// SaveSideBets записывает итоги побочных ставок, рассчитанных при раздаче
+func (r *SQLiteRepository) SaveSideBets(id int64, results []game.SideResult) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to encode side bets: %w", err)
+	}
+
+	if _, err := r.db.Exec(`UPDATE rounds SET side_bets = ? WHERE id = ?`, string(data), id); err != nil {
+		return fmt.Errorf("failed to save side bets: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) Finish(id int64, cards []string, events []game.Event) (*Round, error) {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode events: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE rounds SET cards = ?, events = ?, status = ?, finished_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, strings.Join(cards, ","), string(data), StatusFinished, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finish round: %w", err)
+	}
+	return r.Get(id)
+}
+
+func (r *SQLiteRepository) Get(id int64) (*Round, error) {
+	rnd, err := r.scan(r.db.QueryRow(`
+		SELECT id, chat_id, server_seed, commitment, client_seed, bet, cards, events, side_bets, status, created_at, api
+		FROM rounds WHERE id = ?
+	`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get round: %w", err)
+	}
+	return rnd, nil
+}
+
+// Active возвращает начатые и не завершенные раунды
+func (r *SQLiteRepository) Active() ([]*Round, error) {
+	rows, err := r.db.Query(`
+		SELECT id, chat_id, server_seed, commitment, client_seed, bet, cards, events, side_bets, status, created_at, api
+		FROM rounds WHERE status = ?
+		ORDER BY id
+	`, StatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active rounds: %w", err)
+	}
+	defer rows.Close()
+
+	var rounds []*Round
+	for rows.Next() {
+		rnd, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, rnd)
+	}
+	return rounds, rows.Err()
+}
+
+// History возвращает последние завершенные раунды чата, новые первыми
+func (r *SQLiteRepository) History(chatID int64, limit int) ([]*Round, error) {
+	rows, err := r.db.Query(`
+		SELECT id, chat_id, server_seed, commitment, client_seed, bet, cards, events, side_bets, status, created_at, api
+		FROM rounds WHERE chat_id = ? AND status = ?
+		ORDER BY id DESC LIMIT ?
+	`, chatID, StatusFinished, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get round history: %w", err)
+	}
+	defer rows.Close()
+
+	var rounds []*Round
+	for rows.Next() {
+		rnd, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, rnd)
+	}
+	return rounds, rows.Err()
+}
+
+func (r *SQLiteRepository) scan(row rowScanner) (*Round, error) {
+	var rnd Round
+	var cards, events, sideBets string
+
+	err := row.Scan(
+		&rnd.ID, &rnd.ChatID, &rnd.ServerSeed, &rnd.Commitment,
+		&rnd.ClientSeed, &rnd.Bet, &cards, &events, &sideBets, &rnd.Status, &rnd.CreatedAt, &rnd.API,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if cards != "" {
+		rnd.Cards = strings.Split(cards, ",")
+	}
+	if events != "" {
+		if err := json.Unmarshal([]byte(events), &rnd.Events); err != nil {
+			return nil, fmt.Errorf("failed to decode events: %w", err)
+		}
+	}
+	if sideBets != "" {
+		if err := json.Unmarshal([]byte(sideBets), &rnd.SideBets); err != nil {
+			return nil, fmt.Errorf("failed to decode side bets: %w", err)
+		}
+	}
+	return &rnd, nil
+}
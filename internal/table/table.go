@@ -0,0 +1,252 @@
+package table
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"blackjack/internal/game"
+)
+
+const MaxSeats = 7
+
+var (
+	ErrTableFull     = errors.New("table is full")
+	ErrAlreadySeated = errors.New("already seated")
+	ErrNotSeated     = errors.New("not seated")
+	ErrWrongPhase    = errors.New("not allowed in this phase")
+	ErrAlreadyBet    = errors.New("bet already placed")
+	ErrBetsPlaced    = errors.New("bets are already placed")
+)
+
+type Phase int
+
+const (
+	PhaseBetting Phase = iota
+	PhasePlaying
+)
+
+// Seat — место игрока за столом
+type Seat struct {
+	UserID int64
+	Name   string
+	Bet    int
+}
+
+// Table — стол группового чата: места, фаза ставок и общая игра с дилером
+type Table struct {
+	ChatID   int64
+	Phase    Phase
+	Seats    []*Seat
+	Deadline time.Time
+	Game     *game.State
+
+	// правила, по которым раздаются раунды стола, и дополнительные правила дома
+	Variant game.Variant
+	Rules   game.Rules
+
+	// язык общих сообщений стола — того, кто его открыл
+	Lang string
+
+	// начало текущего хода и сообщение с кнопками для него
+	TurnStarted time.Time
+	MessageID   int
+
+	// boxes[i] — владелец бокса i в текущей игре
+	boxes []*Seat
+	mu    sync.Mutex
+}
+
+func (t *Table) Lock()   { t.mu.Lock() }
+func (t *Table) Unlock() { t.mu.Unlock() }
+
+func (t *Table) Seat(userID int64) *Seat {
+	for _, s := range t.Seats {
+		if s.UserID == userID {
+			return s
+		}
+	}
+	return nil
+}
+
+func (t *Table) Join(userID int64, name string) (*Seat, error) {
+	if t.Seat(userID) != nil {
+		return nil, ErrAlreadySeated
+	}
+	if len(t.Seats) >= MaxSeats {
+		return nil, ErrTableFull
+	}
+
+	seat := &Seat{UserID: userID, Name: name}
+	t.Seats = append(t.Seats, seat)
+	return seat, nil
+}
+
+// Leave освобождает место и возвращает несыгранную ставку
+func (t *Table) Leave(userID int64) (int, error) {
+	if t.Phase != PhaseBetting {
+		return 0, ErrWrongPhase
+	}
+
+	for i, s := range t.Seats {
+		if s.UserID == userID {
+			t.Seats = append(t.Seats[:i], t.Seats[i+1:]...)
+			return s.Bet, nil
+		}
+	}
+	return 0, ErrNotSeated
+}
+
+func (t *Table) PlaceBet(userID int64, amount int) error {
+	if t.Phase != PhaseBetting {
+		return ErrWrongPhase
+	}
+
+	seat := t.Seat(userID)
+	if seat == nil {
+		return ErrNotSeated
+	}
+	if seat.Bet > 0 {
+		return ErrAlreadyBet
+	}
+
+	seat.Bet = amount
+	return nil
+}
+
+// Bettors возвращает места со ставкой в порядке посадки
+func (t *Table) Bettors() []*Seat {
+	var seats []*Seat
+	for _, s := range t.Seats {
+		if s.Bet > 0 {
+			seats = append(seats, s)
+		}
+	}
+	return seats
+}
+
+// SetVariant меняет правила стола; только пока никто не поставил
+func (t *Table) SetVariant(v game.Variant) error {
+	if t.Phase != PhaseBetting {
+		return ErrWrongPhase
+	}
+	if len(t.Bettors()) > 0 {
+		return ErrBetsPlaced
+	}
+	t.Variant = v
+	return nil
+}
+
+// SetRules меняет правила дома; только пока никто не поставил
+func (t *Table) SetRules(r game.Rules) error {
+	if t.Phase != PhaseBetting {
+		return ErrWrongPhase
+	}
+	if len(t.Bettors()) > 0 {
+		return ErrBetsPlaced
+	}
+	t.Rules = r
+	return nil
+}
+
+// AllBet — все сидящие сделали ставку
+func (t *Table) AllBet() bool {
+	return len(t.Seats) > 0 && len(t.Bettors()) == len(t.Seats)
+}
+
+// Deal раздает общую игру: бокс на каждое место со ставкой
+func (t *Table) Deal(src rand.Source) *game.State {
+	t.boxes = t.Bettors()
+
+	bets := make([]int, len(t.boxes))
+	for i, s := range t.boxes {
+		bets[i] = s.Bet
+	}
+
+	t.Game = game.NewRulesState(bets, t.Variant, t.Rules, src)
+	t.Phase = PhasePlaying
+	return t.Game
+}
+
+// Owner возвращает владельца руки
+func (t *Table) Owner(hand *game.Hand) *Seat {
+	if hand == nil || hand.Box >= len(t.boxes) {
+		return nil
+	}
+	return t.boxes[hand.Box]
+}
+
+// CurrentSeat — чей сейчас ход
+func (t *Table) CurrentSeat() *Seat {
+	if t.Game == nil {
+		return nil
+	}
+	return t.Owner(t.Game.Current())
+}
+
+// Boxes возвращает владельцев боксов текущей игры
+func (t *Table) Boxes() []*Seat {
+	return t.boxes
+}
+
+// Reset возвращает стол к приему ставок, игроки остаются на местах
+func (t *Table) Reset() {
+	for _, s := range t.Seats {
+		s.Bet = 0
+	}
+	t.boxes = nil
+	t.Game = nil
+	t.Phase = PhaseBetting
+	t.Deadline = time.Time{}
+	t.TurnStarted = time.Time{}
+	t.MessageID = 0
+}
+
+// Manager хранит столы групповых чатов
+type Manager struct {
+	tables map[int64]*Table
+	mu     sync.Mutex
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		tables: make(map[int64]*Table),
+	}
+}
+
+func (m *Manager) Get(chatID int64) *Table {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tables[chatID]
+}
+
+func (m *Manager) GetOrCreate(chatID int64) *Table {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.tables[chatID]
+	if !ok {
+		t = &Table{ChatID: chatID}
+		m.tables[chatID] = t
+	}
+	return t
+}
+
+func (m *Manager) Delete(chatID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tables, chatID)
+}
+
+// All возвращает снимок всех столов
+func (m *Manager) All() []*Table {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tables := make([]*Table, 0, len(m.tables))
+	for _, t := range m.tables {
+		tables = append(tables, t)
+	}
+	return tables
+}
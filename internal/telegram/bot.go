@@ -0,0 +1,162 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"blackjack/internal/achievement"
+	"blackjack/internal/bot"
+	"blackjack/internal/config"
+	"blackjack/internal/ledger"
+	"blackjack/internal/live"
+	"blackjack/internal/player"
+	"blackjack/internal/round"
+	"blackjack/internal/token"
+	"blackjack/internal/tournament"
+	"blackjack/internal/transport"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Bot — Telegram-фронтенд: принимает апдейты (polling или вебхук)
+// и передает их ядру бота
+type Bot struct {
+	api     *tgbotapi.BotAPI
+	cfg     *config.Config
+	handler *bot.Handler
+	live    *live.Hub
+
+	// обработчики апдейтов, которые еще выполняются
+	wg sync.WaitGroup
+}
+
+func New(cfg *config.Config, repo player.Repository, rounds round.Repository, tokens token.Repository, ledger ledger.Repository, achievements achievement.Repository, tournaments tournament.Repository) (*Bot, error) {
+	api, err := tgbotapi.NewBotAPI(cfg.BotToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var hub *live.Hub
+	if cfg.Live() {
+		hub = live.NewHub(cfg.LiveSecret)
+	}
+
+	return &Bot{
+		api:     api,
+		cfg:     cfg,
+		handler: bot.NewHandler(NewTransport(api), cfg, repo, rounds, tokens, ledger, achievements, tournaments, hub),
+		live:    hub,
+	}, nil
+}
+
+// Run принимает апдейты до отмены ctx, затем дожидается обработчиков
+// и сохраняет незавершенные игры
+func (b *Bot) Run(ctx context.Context) error {
+	log.Printf("Bot started: @%s", b.api.Self.UserName)
+
+	b.handler.Restore()
+	go b.handler.RunSweeper(ctx)
+	if b.live != nil {
+		go b.runLive(ctx)
+	}
+
+	var err error
+	if b.cfg.Webhook() {
+		err = b.runWebhook(ctx)
+	} else {
+		err = b.runPolling(ctx)
+	}
+
+	b.shutdown()
+	return err
+}
+
+func (b *Bot) shutdown() {
+	log.Println("Stopping: waiting for running handlers")
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(b.cfg.ShutdownTimeout):
+		log.Printf("Handlers did not finish in %s, saving games anyway", b.cfg.ShutdownTimeout)
+	}
+
+	b.handler.Shutdown()
+}
+
+func (b *Bot) runPolling(ctx context.Context) error {
+	// getUpdates не работает, пока у бота висит вебхук от прошлого запуска
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return err
+	}
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	updates := b.api.GetUpdatesChan(u)
+	for {
+		select {
+		case <-ctx.Done():
+			b.api.StopReceivingUpdates()
+			return nil
+		case update := <-updates:
+			b.dispatch(update)
+		}
+	}
+}
+
+// dispatch передает апдейт обработчику; одинаково для polling и вебхука
+func (b *Bot) dispatch(update tgbotapi.Update) {
+	if cb := update.CallbackQuery; cb != nil && cb.Message != nil {
+		action := &transport.Action{
+			ID:        cb.ID,
+			ChatID:    cb.Message.Chat.ID,
+			MessageID: cb.Message.MessageID,
+			Group:     isGroup(cb.Message.Chat),
+			From:      user(cb.From),
+			Data:      cb.Data,
+		}
+
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.handler.HandleCallback(action)
+		}()
+		return
+	}
+
+	// каналы и сервисные сообщения без автора не обрабатываем
+	if msg := update.Message; msg != nil && msg.From != nil {
+		message := &transport.Message{
+			ChatID: msg.Chat.ID,
+			Group:  isGroup(msg.Chat),
+			From:   user(msg.From),
+			Text:   msg.Text,
+		}
+
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.handler.HandleMessage(message)
+		}()
+	}
+}
+
+func isGroup(chat *tgbotapi.Chat) bool {
+	return chat.IsGroup() || chat.IsSuperGroup()
+}
+
+func user(u *tgbotapi.User) transport.User {
+	name := u.FirstName
+	if u.UserName != "" {
+		name = "@" + u.UserName
+	}
+	return transport.User{ID: u.ID, Name: name, LanguageCode: u.LanguageCode}
+}
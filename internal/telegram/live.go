@@ -0,0 +1,34 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// runLive раздает трансляцию столов по WebSocket до отмены ctx
+func (b *Bot) runLive(ctx context.Context) {
+	srv := &http.Server{
+		Addr:    b.cfg.LiveListen,
+		Handler: b.live.Handler(),
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		sctx, cancel := context.WithTimeout(context.Background(), b.cfg.ShutdownTimeout)
+		defer cancel()
+
+		// WebSocket-соединения сервер уже не отслеживает, закрываем их сами
+		b.live.Close()
+		if err := srv.Shutdown(sctx); err != nil {
+			log.Printf("Failed to stop live server: %v", err)
+		}
+	}()
+
+	log.Printf("Live view listening on %s", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Live server error: %v", err)
+	}
+}
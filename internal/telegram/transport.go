@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"blackjack/internal/transport"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Transport отправляет сообщения ядра бота через Bot API
+type Transport struct {
+	api *tgbotapi.BotAPI
+}
+
+func NewTransport(api *tgbotapi.BotAPI) *Transport {
+	return &Transport{api: api}
+}
+
+func (t *Transport) Send(chatID int64, text string, kb transport.Keyboard) (int, error) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	if kb != nil {
+		msg.ReplyMarkup = markup(kb)
+	}
+
+	sent, err := t.api.Send(msg)
+	if err != nil {
+		return 0, err
+	}
+	return sent.MessageID, nil
+}
+
+func (t *Transport) Edit(chatID int64, messageID int, text string, kb transport.Keyboard) error {
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	if kb != nil {
+		m := markup(kb)
+		msg.ReplyMarkup = &m
+	}
+
+	_, err := t.api.Send(msg)
+	return err
+}
+
+func (t *Transport) SendPhoto(chatID int64, photo []byte, caption string, kb transport.Keyboard) (int, error) {
+	msg := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: "table.png", Bytes: photo})
+	msg.Caption = caption
+	if kb != nil {
+		msg.ReplyMarkup = markup(kb)
+	}
+
+	sent, err := t.api.Send(msg)
+	if err != nil {
+		return 0, err
+	}
+	return sent.MessageID, nil
+}
+
+func (t *Transport) EditPhoto(chatID int64, messageID int, photo []byte, caption string, kb transport.Keyboard) error {
+	media := tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{Name: "table.png", Bytes: photo})
+	media.Caption = caption
+
+	msg := tgbotapi.EditMessageMediaConfig{
+		BaseEdit: tgbotapi.BaseEdit{ChatID: chatID, MessageID: messageID},
+		Media:    media,
+	}
+	if kb != nil {
+		m := markup(kb)
+		msg.ReplyMarkup = &m
+	}
+
+	_, err := t.api.Send(msg)
+	return err
+}
+
+func (t *Transport) Answer(actionID, text string) error {
+	_, err := t.api.Request(tgbotapi.NewCallback(actionID, text))
+	return err
+}
+
+func markup(kb transport.Keyboard) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(kb))
+	for _, row := range kb {
+		buttons := make([]tgbotapi.InlineKeyboardButton, 0, len(row))
+		for _, b := range row {
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(b.Text, b.Data))
+		}
+		rows = append(rows, buttons)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
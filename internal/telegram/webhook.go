@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// secretHeader — заголовок, в котором Telegram присылает secret_token из setWebhook
+const secretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+func (b *Bot) runWebhook(ctx context.Context) error {
+	if err := b.setWebhook(); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	u, _ := url.Parse(b.cfg.WebhookURL)
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, b.webhookHandler())
+
+	srv := &http.Server{
+		Addr:    b.cfg.WebhookListen,
+		Handler: mux,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		if b.cfg.TLSCertFile != "" {
+			log.Printf("Webhook listening on %s (HTTPS), path %s", srv.Addr, path)
+			errc <- srv.ListenAndServeTLS(b.cfg.TLSCertFile, b.cfg.TLSKeyFile)
+			return
+		}
+
+		log.Printf("Webhook listening on %s, path %s", srv.Addr, path)
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	// вебхук не снимаем: пока бот лежит, Telegram копит апдейты у себя
+	sctx, cancel := context.WithTimeout(context.Background(), b.cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(sctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// setWebhook регистрирует адрес вебхука. WebhookConfig из tgbotapi
+// не знает про secret_token, поэтому запрос собирается вручную.
+func (b *Bot) setWebhook() error {
+	params := tgbotapi.Params{
+		"url":          b.cfg.WebhookURL,
+		"secret_token": b.cfg.WebhookSecret,
+	}
+	params.AddInterface("allowed_updates", []string{"message", "callback_query"})
+
+	_, err := b.api.MakeRequest("setWebhook", params)
+	return err
+}
+
+func (b *Bot) webhookHandler() http.HandlerFunc {
+	secret := []byte(b.cfg.WebhookSecret)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(secretHeader)), secret) != 1 {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		update, err := b.api.HandleUpdate(r)
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		b.dispatch(*update)
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,75 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("token not found")
+
+// Repository выдает и проверяет токены HTTP API.
+// У игрока один действующий токен: новый отзывает старый.
+type Repository interface {
+	Issue(userID int64) (string, error)
+	UserID(token string) (int64, error)
+}
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+func (r *SQLiteRepository) Issue(userID int64) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM api_tokens WHERE user_id = ?`, userID); err != nil {
+		return "", fmt.Errorf("failed to revoke token: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO api_tokens (token_hash, user_id) VALUES (?, ?)
+	`, hash(token), userID); err != nil {
+		return "", fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (r *SQLiteRepository) UserID(token string) (int64, error) {
+	var userID int64
+	err := r.db.QueryRow(`
+		SELECT user_id FROM api_tokens WHERE token_hash = ?
+	`, hash(token)).Scan(&userID)
+
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to check token: %w", err)
+	}
+	return userID, nil
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
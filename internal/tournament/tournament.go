@@ -0,0 +1,419 @@
+package tournament
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackjack/internal/ledger"
+)
+
+var (
+	ErrNotFound    = errors.New("tournament not found")
+	ErrExists      = errors.New("another tournament is scheduled or running")
+	ErrClosed      = errors.New("registration is closed")
+	ErrRegistered  = errors.New("already registered")
+	ErrNotEntered  = errors.New("not registered")
+	ErrNoRounds    = errors.New("no rounds left")
+	ErrNoChips     = errors.New("not enough tournament chips")
+	ErrRoundActive = errors.New("round already in progress")
+)
+
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled"
+	StatusRunning   Status = "running"
+	StatusFinished  Status = "finished"
+)
+
+// Tournament — турнир из фиксированного числа раундов. Фишки турнира
+// у каждого участника свои и с основным балансом не смешиваются;
+// Prizes — призы за места по порядку, они платятся на основной баланс.
+type Tournament struct {
+	ID        int64
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Rounds    int
+	Chips     int
+	Prizes    []int
+	Status    Status
+	CreatedBy int64
+}
+
+// Open — можно ли еще зарегистрироваться
+func (t *Tournament) Open() bool {
+	return t.Status == StatusScheduled
+}
+
+// Entry — участник турнира. Name — подпись в таблице, RoundID — идущий
+// турнирный раунд (0 — нет), MessageID — сообщение с таблицей в личном чате,
+// которое обновляется после раундов.
+type Entry struct {
+	TournamentID int64
+	UserID       int64
+	Name         string
+	Balance      int
+	Played       int
+	RoundID      int64
+	MessageID    int
+	Prize        int
+}
+
+// Done — участник больше не сыграет: раунды кончились или фишек не хватает на ставку
+func (e *Entry) Done(t *Tournament, minBet int) bool {
+	return e.RoundID == 0 && (e.Played >= t.Rounds || e.Balance < minBet)
+}
+
+type Repository interface {
+	Create(t *Tournament) error
+	Current() (*Tournament, error)
+	Get(id int64) (*Tournament, error)
+	Start(id int64) (bool, error)
+	Finish(id int64) ([]Entry, error)
+
+	Register(id, userID int64, name string, chips int) (*Entry, error)
+	Entry(id, userID int64) (*Entry, error)
+	Standings(id int64) ([]Entry, error)
+	ByRound(roundID int64) (*Entry, error)
+	SetMessage(id, userID int64, messageID int) error
+
+	StartRound(id, userID, roundID int64, rounds, bet int) (*Entry, error)
+	SetBalance(id, userID int64, balance int) error
+	FinishRound(id, userID int64, balance int) (*Entry, error)
+	ForfeitRound(id, userID, roundID int64) error
+}
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Create планирует турнир; одновременно может быть только один незавершенный
+func (r *SQLiteRepository) Create(t *Tournament) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		INSERT INTO tournaments (starts_at, ends_at, rounds, chips, prizes, status, created_by)
+		SELECT ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM tournaments WHERE status <> ?)
+	`, t.StartsAt.Unix(), t.EndsAt.Unix(), t.Rounds, t.Chips, formatPrizes(t.Prizes), StatusScheduled, t.CreatedBy,
+		StatusFinished)
+	if err != nil {
+		return fmt.Errorf("failed to create tournament: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrExists
+	}
+
+	if t.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to create tournament: %w", err)
+	}
+	t.Status = StatusScheduled
+	return tx.Commit()
+}
+
+// Current — запланированный или идущий турнир
+func (r *SQLiteRepository) Current() (*Tournament, error) {
+	return r.scan(r.db.QueryRow(`
+		SELECT id, starts_at, ends_at, rounds, chips, prizes, status, created_by
+		FROM tournaments WHERE status <> ? ORDER BY id LIMIT 1
+	`, StatusFinished))
+}
+
+func (r *SQLiteRepository) Get(id int64) (*Tournament, error) {
+	return r.scan(r.db.QueryRow(`
+		SELECT id, starts_at, ends_at, rounds, chips, prizes, status, created_by
+		FROM tournaments WHERE id = ?
+	`, id))
+}
+
+func (r *SQLiteRepository) scan(row *sql.Row) (*Tournament, error) {
+	var t Tournament
+	var startsAt, endsAt int64
+	var prizes string
+
+	err := row.Scan(&t.ID, &startsAt, &endsAt, &t.Rounds, &t.Chips, &prizes, &t.Status, &t.CreatedBy)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament: %w", err)
+	}
+
+	t.StartsAt = time.Unix(startsAt, 0)
+	t.EndsAt = time.Unix(endsAt, 0)
+	if t.Prizes, err = ParsePrizes(prizes); err != nil {
+		return nil, fmt.Errorf("failed to get tournament: %w", err)
+	}
+	return &t, nil
+}
+
+// Start открывает игру; false — турнир уже запущен или завершен
+func (r *SQLiteRepository) Start(id int64) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE tournaments SET status = ? WHERE id = ? AND status = ?
+	`, StatusRunning, id, StatusScheduled)
+	if err != nil {
+		return false, fmt.Errorf("failed to start tournament: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Finish завершает турнир и одной транзакцией платит призы на основной
+// баланс через журнал. Возвращает итоговую таблицу с призами; ErrNotFound —
+// турнир уже завершен (например, параллельным вызовом).
+func (r *SQLiteRepository) Finish(id int64) ([]Entry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// сначала запись: второй вызов дождется транзакции и не пройдет условие
+	var prizes string
+	err = tx.QueryRow(`
+		UPDATE tournaments SET status = ? WHERE id = ? AND status = ?
+		RETURNING prizes
+	`, StatusFinished, id, StatusRunning).Scan(&prizes)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to finish tournament: %w", err)
+	}
+
+	amounts, err := ParsePrizes(prizes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finish tournament: %w", err)
+	}
+
+	entries, err := standings(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if i >= len(amounts) {
+			break
+		}
+		e := &entries[i]
+		e.Prize = amounts[i]
+
+		if _, err := ledger.Credit(tx, e.UserID, ledger.KindPrize, e.Prize); err != nil {
+			return nil, fmt.Errorf("failed to pay prize to %d: %w", e.UserID, err)
+		}
+		if _, err := tx.Exec(`
+			UPDATE tournament_players SET prize = ? WHERE tournament_id = ? AND user_id = ?
+		`, e.Prize, id, e.UserID); err != nil {
+			return nil, fmt.Errorf("failed to save prize: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Register записывает игрока и выдает ему турнирные фишки
+func (r *SQLiteRepository) Register(id, userID int64, name string, chips int) (*Entry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		INSERT OR IGNORE INTO tournament_players (tournament_id, user_id, name, balance)
+		SELECT id, ?, ?, ? FROM tournaments WHERE id = ? AND status = ?
+	`, userID, name, chips, id, StatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register: %w", err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		var status Status
+		err := tx.QueryRow(`SELECT status FROM tournaments WHERE id = ?`, id).Scan(&status)
+		switch {
+		case err == sql.ErrNoRows:
+			return nil, ErrNotFound
+		case err != nil:
+			return nil, fmt.Errorf("failed to get tournament: %w", err)
+		case status != StatusScheduled:
+			return nil, ErrClosed
+		}
+		return nil, ErrRegistered
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &Entry{TournamentID: id, UserID: userID, Name: name, Balance: chips}, nil
+}
+
+const entryColumns = `tournament_id, user_id, name, balance, played, round_id, message_id, prize`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(s scanner) (*Entry, error) {
+	var e Entry
+	err := s.Scan(&e.TournamentID, &e.UserID, &e.Name, &e.Balance, &e.Played, &e.RoundID, &e.MessageID, &e.Prize)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotEntered
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament entry: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *SQLiteRepository) Entry(id, userID int64) (*Entry, error) {
+	return scanEntry(r.db.QueryRow(`
+		SELECT `+entryColumns+` FROM tournament_players WHERE tournament_id = ? AND user_id = ?
+	`, id, userID))
+}
+
+// ByRound находит участника по идущему турнирному раунду — для восстановления после перезапуска
+func (r *SQLiteRepository) ByRound(roundID int64) (*Entry, error) {
+	return scanEntry(r.db.QueryRow(`
+		SELECT `+entryColumns+` FROM tournament_players WHERE round_id = ?
+	`, roundID))
+}
+
+// Standings — участники по убыванию турнирных фишек; при равенстве выше тот,
+// кто раньше зарегистрировался
+func (r *SQLiteRepository) Standings(id int64) ([]Entry, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return standings(tx, id)
+}
+
+func standings(tx *sql.Tx, id int64) ([]Entry, error) {
+	rows, err := tx.Query(`
+		SELECT `+entryColumns+` FROM tournament_players
+		WHERE tournament_id = ? ORDER BY balance DESC, rowid
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standings: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *SQLiteRepository) SetMessage(id, userID int64, messageID int) error {
+	_, err := r.db.Exec(`
+		UPDATE tournament_players SET message_id = ? WHERE tournament_id = ? AND user_id = ?
+	`, messageID, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to save standings message: %w", err)
+	}
+	return nil
+}
+
+// StartRound списывает ставку с турнирных фишек и отмечает идущий раунд.
+// Проверки и списание — одно обновление, так что второй раунд параллельно не начнется.
+func (r *SQLiteRepository) StartRound(id, userID, roundID int64, rounds, bet int) (*Entry, error) {
+	e, err := scanEntry(r.db.QueryRow(`
+		UPDATE tournament_players SET balance = balance - ?, round_id = ?
+		WHERE tournament_id = ? AND user_id = ? AND round_id = 0 AND played < ? AND balance >= ?
+		RETURNING `+entryColumns, bet, roundID, id, userID, rounds, bet))
+	if !errors.Is(err, ErrNotEntered) {
+		return e, err
+	}
+
+	// объясняем, какое условие не прошло
+	e, err = r.Entry(id, userID)
+	switch {
+	case err != nil:
+		return nil, err
+	case e.RoundID != 0:
+		return nil, ErrRoundActive
+	case e.Played >= rounds:
+		return nil, ErrNoRounds
+	}
+	return nil, ErrNoChips
+}
+
+// SetBalance сохраняет фишки посреди раунда — после удвоения или сплита
+func (r *SQLiteRepository) SetBalance(id, userID int64, balance int) error {
+	_, err := r.db.Exec(`
+		UPDATE tournament_players SET balance = ? WHERE tournament_id = ? AND user_id = ?
+	`, balance, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to save tournament balance: %w", err)
+	}
+	return nil
+}
+
+// FinishRound записывает фишки после расчета и засчитывает сыгранный раунд
+func (r *SQLiteRepository) FinishRound(id, userID int64, balance int) (*Entry, error) {
+	return scanEntry(r.db.QueryRow(`
+		UPDATE tournament_players SET balance = ?, played = played + 1, round_id = 0
+		WHERE tournament_id = ? AND user_id = ?
+		RETURNING `+entryColumns, balance, id, userID))
+}
+
+// ForfeitRound засчитывает раунд, который уже не доиграть, сыгранным:
+// ставка остается списанной. Ничего не делает, если участник уже в другом раунде.
+func (r *SQLiteRepository) ForfeitRound(id, userID, roundID int64) error {
+	_, err := r.db.Exec(`
+		UPDATE tournament_players SET played = played + 1, round_id = 0
+		WHERE tournament_id = ? AND user_id = ? AND round_id = ?
+	`, id, userID, roundID)
+	if err != nil {
+		return fmt.Errorf("failed to forfeit tournament round: %w", err)
+	}
+	return nil
+}
+
+// ParsePrizes читает призы за места: "500,300,100"
+func ParsePrizes(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var prizes []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid prize %q", part)
+		}
+		prizes = append(prizes, n)
+	}
+	return prizes, nil
+}
+
+func formatPrizes(prizes []int) string {
+	parts := make([]string, len(prizes))
+	for i, p := range prizes {
+		parts[i] = strconv.Itoa(p)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,48 @@
+package transport
+
+// User — автор сообщения или нажатия
+type User struct {
+	ID           int64
+	Name         string // как обращаться в общем чате: @username или имя
+	LanguageCode string
+}
+
+// Message — текстовое сообщение в чат
+type Message struct {
+	ChatID int64
+	Group  bool
+	From   User
+	Text   string
+}
+
+// Action — нажатие кнопки под сообщением
+type Action struct {
+	ID        string // для ответа через Answer
+	ChatID    int64
+	MessageID int
+	Group     bool
+	From      User
+	Data      string
+}
+
+type Button struct {
+	Text string
+	Data string
+}
+
+// Keyboard — кнопки под сообщением по рядам; nil — без кнопок
+type Keyboard [][]Button
+
+// Transport доставляет сообщения игрокам: Telegram, терминал и т.п.
+type Transport interface {
+	// Send отправляет сообщение и возвращает его ID для последующего Edit
+	Send(chatID int64, text string, kb Keyboard) (int, error)
+	// Edit заменяет текст и кнопки отправленного сообщения
+	Edit(chatID int64, messageID int, text string, kb Keyboard) error
+	// SendPhoto отправляет картинку PNG с подписью и кнопками и возвращает ID сообщения
+	SendPhoto(chatID int64, photo []byte, caption string, kb Keyboard) (int, error)
+	// EditPhoto заменяет картинку, подпись и кнопки сообщения из SendPhoto
+	EditPhoto(chatID int64, messageID int, photo []byte, caption string, kb Keyboard) error
+	// Answer подтверждает нажатие кнопки; непустой текст показывается всплывающей подсказкой
+	Answer(actionID, text string) error
+}